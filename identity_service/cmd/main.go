@@ -20,6 +20,7 @@ func init() {
 
 	config.LoadEnv()
 	db.Connect()
+	db.Migrate()
 }
 
 func main() {
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/joy095/identity/logger"
+	"github.com/joy095/identity/utils/username"
+)
+
+// Unique indexes that keep usernames apart: one per name in any case, and one
+// per confusable skeleton
+const (
+	UsernameLowerIndex    = "users_username_lower_key"
+	UsernameSkeletonIndex = "users_username_skeleton_key"
+)
+
+// migrations bring the users table up to what the username policy needs.
+// Each one is safe to run again.
+var migrations = []string{
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS username_skeleton VARCHAR(255)`,
+	// Users registered before the column existed get their skeleton computed in place
+	`UPDATE users SET username_skeleton = ` + username.SkeletonSQL("username") + ` WHERE username_skeleton IS NULL`,
+	`ALTER TABLE users ALTER COLUMN username_skeleton SET NOT NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS ` + UsernameLowerIndex + ` ON users (LOWER(username))`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS ` + UsernameSkeletonIndex + ` ON users (username_skeleton)`,
+}
+
+// Migrate applies the migrations, exiting if one fails. Existing usernames
+// that clash with each other make the unique indexes fail, and have to be
+// renamed before the service will start.
+func Migrate() {
+	for _, query := range migrations {
+		if _, err := DB.Exec(context.Background(), query); err != nil {
+			logger.ErrorLogger.Error("Migration failed on query:", query, "Error:", err)
+			fmt.Println("Migration failed on query:", query, "\nError:", err)
+			os.Exit(1)
+		}
+	}
+
+	logger.InfoLogger.Info("Database migration completed successfully!")
+	fmt.Println("Database migration completed successfully!")
+}
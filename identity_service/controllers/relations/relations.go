@@ -179,6 +179,104 @@ func (r *RelationController) ListConnections(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"connections": connections})
 }
 
+// BlockUser blocks another user. Any existing connection or request between the
+// two is replaced, so the block also cuts off direct messaging in message-service.
+func (r *RelationController) BlockUser(c *gin.Context) {
+	var payload struct {
+		UserID string `json:"user_id"`
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	blockerID := c.GetString("user_id")
+
+	if _, err := uuid.Parse(payload.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user UUID format"})
+		return
+	}
+
+	if blockerID == payload.UserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot block yourself"})
+		return
+	}
+
+	tx, err := db.DB.Begin(context.Background())
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to block user"})
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	// Drop the reverse row unless the other side has blocked us too
+	_, err = tx.Exec(context.Background(), `
+		DELETE FROM user_connections
+		WHERE requester_id = $2 AND addressee_id = $1 AND status <> 'blocked'
+	`, blockerID, payload.UserID)
+	if err != nil {
+		log.Printf("Error removing existing relationship: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to block user"})
+		return
+	}
+
+	_, err = tx.Exec(context.Background(), `
+		INSERT INTO user_connections (requester_id, addressee_id, status)
+		VALUES ($1, $2, 'blocked')
+		ON CONFLICT (requester_id, addressee_id)
+		DO UPDATE SET status = 'blocked', updated_at = CURRENT_TIMESTAMP
+	`, blockerID, payload.UserID)
+	if err != nil {
+		log.Printf("Error blocking user: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to block user"})
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		log.Printf("Error committing block: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to block user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User blocked"})
+}
+
+// UnblockUser lifts a block the caller placed on another user
+func (r *RelationController) UnblockUser(c *gin.Context) {
+	var payload struct {
+		UserID string `json:"user_id"`
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	blockerID := c.GetString("user_id")
+
+	if _, err := uuid.Parse(payload.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user UUID format"})
+		return
+	}
+
+	tag, err := db.DB.Exec(context.Background(), `
+		DELETE FROM user_connections
+		WHERE requester_id = $1 AND addressee_id = $2 AND status = 'blocked'
+	`, blockerID, payload.UserID)
+	if err != nil {
+		log.Printf("Error unblocking user: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unblock user"})
+		return
+	}
+
+	if tag.RowsAffected() == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User is not blocked"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unblocked"})
+}
+
 func (r *RelationController) CheckConnectionStatus(c *gin.Context) {
 	userID := c.GetString("user_id")
 	targetID := c.Param("user_id")
@@ -1,12 +1,9 @@
 package controllers
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -16,6 +13,8 @@ import (
 	"github.com/joy095/identity/models"
 
 	"github.com/joy095/identity/utils/mail"
+	"github.com/joy095/identity/utils/username"
+	"github.com/joy095/word-filter/client"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -27,11 +26,15 @@ func init() {
 }
 
 // UserController handles user-related requests
-type UserController struct{}
+type UserController struct {
+	wordFilter *client.Client
+}
 
 // NewUserController creates a new UserController
 func NewUserController() *UserController {
-	return &UserController{}
+	return &UserController{
+		wordFilter: client.New(client.ConfigFromEnv()),
+	}
 }
 
 // Register handles user registration
@@ -51,57 +54,43 @@ func (uc *UserController) Register(c *gin.Context) {
 		return
 	}
 
-	// Check if username contains bad words by calling the word filter service
-	requestBody, err := json.Marshal(map[string]string{
-		"text": req.Username,
-	})
-
-	if err != nil {
-		logger.ErrorLogger.Error(err, "Failed to prepare validation request")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to prepare validation request"})
+	if errs := username.Validate(req.Username); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid username", "fields": errs})
 		return
 	}
 
-	wordFilterService := os.Getenv("WORD_FILTER_SERVICE_URL")
-	if wordFilterService == "" {
-		logger.ErrorLogger.Error("WORD_FILTER_SERVICE_URL environment variable is not set")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Word filter service configuration is missing"})
-		return
-	}
-
-	response, err := http.Post(
-		wordFilterService+"/check",
-		"application/json",
-		bytes.NewBuffer(requestBody),
-	)
+	// Check if username contains bad words by calling the word filter service
+	verdict, err := uc.wordFilter.Check(c.Request.Context(), client.CheckRequest{Text: req.Username})
 	logger.InfoLogger.Info("Word Filter Service Called")
 
+	if errors.Is(err, client.ErrUnavailable) {
+		logger.ErrorLogger.Error(err, "Word filter service unavailable")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Username can't be checked right now, try again later"})
+		return
+	}
 	if err != nil {
-		logger.ErrorLogger.Error("errors", err, "Failed to validate username")
+		logger.ErrorLogger.Error(err, "Failed to validate username")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate username"})
 		return
 	}
-	defer response.Body.Close()
-
-	var wordFilterResponse struct {
-		ContainsBadWords bool `json:"containsBadWords"`
-	}
 
-	if err := json.NewDecoder(response.Body).Decode(&wordFilterResponse); err != nil {
-		logger.ErrorLogger.Error(err, "Failed to decode validation response")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode validation response"})
+	if verdict.ContainsBadWords {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid username",
+			"fields": []username.FieldError{username.Error(username.CodeInappropriate, "Username contains inappropriate words")},
+		})
 		return
 	}
 
-	if wordFilterResponse.ContainsBadWords {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Username contains inappropriate words"})
+	// Checked up front for a quick answer; the database enforces it again on insert
+	if err := models.UsernameConflict(db.DB, req.Username); err != nil {
+		usernameConflict(c, err)
 		return
 	}
 
 	user, accessToken, refreshToken, err := models.CreateUser(db.DB, req.Username, req.Email, req.Password)
 	if err != nil {
-		logger.ErrorLogger.Error(err, "Failed to create user")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		usernameConflict(c, err)
 		return
 	}
 
@@ -125,6 +114,26 @@ func (uc *UserController) Register(c *gin.Context) {
 	logger.InfoLogger.Info("User registered successfully")
 }
 
+// usernameConflict answers a registration whose username clashes with an
+// existing one, or that failed for another reason
+func usernameConflict(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrUsernameTaken):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "Username is not available",
+			"fields": []username.FieldError{username.Error(username.CodeTaken, "Username is already taken")},
+		})
+	case errors.Is(err, models.ErrUsernameConfusable):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "Username is not available",
+			"fields": []username.FieldError{username.Error(username.CodeConfusable, "Username is too similar to an existing one")},
+		})
+	default:
+		logger.ErrorLogger.Error(err, "Failed to create user")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+	}
+}
+
 // Login handles user login
 func (uc *UserController) Login(c *gin.Context) {
 	logger.InfoLogger.Info("Login handler called")
@@ -12,10 +12,13 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/joy095/identity/config/db"
 	"github.com/joy095/identity/logger"
 	"github.com/joy095/identity/utils"
+	"github.com/joy095/identity/utils/username"
 
 	"golang.org/x/crypto/argon2"
 )
@@ -212,7 +215,7 @@ func ValidateAccessToken(tokenString string) (*jwt.Token, jwt.MapClaims, error)
 }
 
 // CreateUser registers a new user and returns JWT & refresh token
-func CreateUser(db *pgxpool.Pool, username, email, password string) (*User, string, string, error) {
+func CreateUser(db *pgxpool.Pool, name, email, password string) (*User, string, string, error) {
 	logger.InfoLogger.Info("CreateUser called on models")
 
 	passwordHash, err := HashPassword(password)
@@ -235,16 +238,16 @@ func CreateUser(db *pgxpool.Pool, username, email, password string) (*User, stri
 		return nil, "", "", err
 	}
 
-	query := `INSERT INTO users (id, username, email, password_hash, refresh_token) 
-              VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	_, err = db.Exec(context.Background(), query, userID, username, email, passwordHash, refreshToken)
+	query := `INSERT INTO users (id, username, username_skeleton, email, password_hash, refresh_token) 
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	_, err = db.Exec(context.Background(), query, userID, name, username.Skeleton(name), email, passwordHash, refreshToken)
 	if err != nil {
-		return nil, "", "", err
+		return nil, "", "", usernameError(err)
 	}
 
 	user := &User{
 		ID:           userID,
-		Username:     username,
+		Username:     name,
 		Email:        email,
 		PasswordHash: passwordHash,
 		RefreshToken: &refreshToken,
@@ -294,10 +297,10 @@ func LogoutUser(db *pgxpool.Pool, userID uuid.UUID) error {
 	return err
 }
 
-// GetUserByUsername retrieves a user by username
+// GetUserByUsername retrieves a user by username, in any case
 func GetUserByUsername(db *pgxpool.Pool, username string) (*User, error) {
 	var user User
-	query := `SELECT id, username, email, password_hash, refresh_token FROM users WHERE username = $1`
+	query := `SELECT id, username, email, password_hash, refresh_token FROM users WHERE LOWER(username) = LOWER($1)`
 	err := db.QueryRow(context.Background(), query, username).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.RefreshToken,
 	)
@@ -306,3 +309,53 @@ func GetUserByUsername(db *pgxpool.Pool, username string) (*User, error) {
 	}
 	return &user, nil
 }
+
+// Errors returned when a username clashes with an existing user's
+var (
+	ErrUsernameTaken      = errors.New("username is already taken")
+	ErrUsernameConfusable = errors.New("username is too similar to an existing one")
+)
+
+// usernameError maps a unique violation of one of the username indexes to
+// ErrUsernameTaken or ErrUsernameConfusable
+func usernameError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+	switch pgErr.ConstraintName {
+	case db.UsernameLowerIndex:
+		return ErrUsernameTaken
+	case db.UsernameSkeletonIndex:
+		return ErrUsernameConfusable
+	}
+	return err
+}
+
+// UsernameConflict reports how name clashes with an existing user:
+// ErrUsernameTaken if one has the same name in any case,
+// ErrUsernameConfusable if one has a name with the same skeleton, or nil if
+// it's free. CreateUser enforces the same through unique indexes; this lets a
+// clash be reported before the password is hashed.
+func UsernameConflict(pool *pgxpool.Pool, name string) error {
+	logger.InfoLogger.Info("UsernameConflict called on models")
+
+	var taken, confusable bool
+	query := `SELECT
+                  COALESCE(bool_or(LOWER(username) = LOWER($1)), false),
+                  COALESCE(bool_or(username_skeleton = $2), false)
+              FROM users
+              WHERE LOWER(username) = LOWER($1) OR username_skeleton = $2`
+	err := pool.QueryRow(context.Background(), query, name, username.Skeleton(name)).Scan(&taken, &confusable)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case taken:
+		return ErrUsernameTaken
+	case confusable:
+		return ErrUsernameConfusable
+	}
+	return nil
+}
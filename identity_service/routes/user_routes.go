@@ -34,6 +34,8 @@ func RegisterRoutes(router *gin.Engine) {
 		protected.POST("/relation/reject", middleware.NewRateLimiter("30-1m"), relationController.RejectRequest)
 		protected.GET("/relation/pending", middleware.NewRateLimiter("30-1m"), relationController.ListPendingRequests)
 		protected.GET("/relation/connections", middleware.NewRateLimiter("30-1m"), relationController.ListConnections)
+		protected.POST("/relation/block", middleware.NewRateLimiter("30-1m"), relationController.BlockUser)
+		protected.POST("/relation/unblock", middleware.NewRateLimiter("30-1m"), relationController.UnblockUser)
 		protected.GET("/relation/status/:user_id", middleware.NewRateLimiter("30-1m"), relationController.CheckConnectionStatus)
 	}
 }
@@ -0,0 +1,157 @@
+// Package username is the policy a new username has to meet: length and
+// charset rules, reserved names, and the confusable skeleton used to keep
+// look-alike names such as paypal and paypa1 from both being registered.
+package username
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Length limits, in characters
+const (
+	MinLength = 3
+	MaxLength = 30
+)
+
+// Violation codes
+const (
+	CodeTooShort          = "too_short"
+	CodeTooLong           = "too_long"
+	CodeInvalidCharacters = "invalid_characters"
+	CodeInvalidStart      = "invalid_start"
+	CodeInvalidSeparator  = "invalid_separator"
+	CodeReserved          = "reserved"
+	CodeInappropriate     = "inappropriate"
+	CodeTaken             = "taken"
+	CodeConfusable        = "confusable"
+)
+
+// FieldError is one rule a request field breaks
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error builds a FieldError for the username field
+func Error(code, message string) FieldError {
+	return FieldError{Field: "username", Code: code, Message: message}
+}
+
+// reserved are names kept for the platform itself. They are compared by
+// skeleton, so adm1n and Support are reserved too.
+var reserved = []string{
+	"admin", "administrator", "root", "system", "sysadmin", "superuser",
+	"support", "help", "helpdesk", "contact", "info", "security", "abuse",
+	"moderator", "mod", "staff", "official", "team",
+	"api", "www", "mail", "email", "noreply", "postmaster", "webmaster",
+	"login", "logout", "register", "signup", "signin", "auth", "account",
+	"settings", "user", "users", "null", "undefined", "anonymous",
+}
+
+var reservedSkeletons = func() map[string]bool {
+	skeletons := make(map[string]bool, len(reserved))
+	for _, name := range reserved {
+		skeletons[Skeleton(name)] = true
+	}
+	return skeletons
+}()
+
+// Validate checks name against the rules that don't need the database and
+// returns every one it breaks
+func Validate(name string) []FieldError {
+	var errs []FieldError
+
+	length := len([]rune(name))
+	if length < MinLength {
+		errs = append(errs, Error(CodeTooShort, fmt.Sprintf("Username must be at least %d characters", MinLength)))
+	}
+	if length > MaxLength {
+		errs = append(errs, Error(CodeTooLong, fmt.Sprintf("Username must be at most %d characters", MaxLength)))
+	}
+
+	for _, r := range name {
+		if !isLetter(r) && !isDigit(r) && !isSeparator(r) {
+			errs = append(errs, Error(CodeInvalidCharacters, "Username may only contain letters, digits, '_' and '.'"))
+			break
+		}
+	}
+
+	if name != "" && !isLetter(rune(name[0])) {
+		errs = append(errs, Error(CodeInvalidStart, "Username must start with a letter"))
+	}
+
+	if strings.HasSuffix(name, "_") || strings.HasSuffix(name, ".") || hasDoubleSeparator(name) {
+		errs = append(errs, Error(CodeInvalidSeparator, "Username can't end with '_' or '.' or have two of them in a row"))
+	}
+
+	if len(errs) == 0 && reservedSkeletons[Skeleton(name)] {
+		errs = append(errs, Error(CodeReserved, "Username is reserved"))
+	}
+
+	return errs
+}
+
+func isLetter(r rune) bool    { return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' }
+func isDigit(r rune) bool     { return '0' <= r && r <= '9' }
+func isSeparator(r rune) bool { return r == '_' || r == '.' }
+
+func hasDoubleSeparator(name string) bool {
+	for i := 1; i < len(name); i++ {
+		if isSeparator(rune(name[i-1])) && isSeparator(rune(name[i])) {
+			return true
+		}
+	}
+	return false
+}
+
+// Characters folded onto the letter they pass for, in the same order as
+// their replacements
+const (
+	confusableFrom = "01i345278"
+	confusableTo   = "olleasztb"
+)
+
+// separators are dropped from the skeleton, so pay_pal collides with paypal
+const separators = "._"
+
+// Letter pairs that pass for a single letter, folded in order
+var confusablePairs = [][2]string{
+	{"rn", "m"},
+	{"vv", "w"},
+}
+
+// Skeleton reduces name to the form look-alike names share: lowercased, with
+// digits and letters that pass for other letters folded onto them, and
+// separators dropped. Two names with the same skeleton are confusable.
+func Skeleton(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if strings.ContainsRune(separators, r) {
+			continue
+		}
+		if i := strings.IndexRune(confusableFrom, r); i >= 0 {
+			r = rune(confusableTo[i])
+		}
+		b.WriteRune(r)
+	}
+
+	skeleton := b.String()
+	for _, pair := range confusablePairs {
+		skeleton = strings.ReplaceAll(skeleton, pair[0], pair[1])
+	}
+	return skeleton
+}
+
+// SkeletonSQL returns a PostgreSQL expression computing Skeleton of column,
+// for filling in the skeletons of names stored before they were kept
+func SkeletonSQL(column string) string {
+	// translate drops the characters that have no replacement, which is how
+	// the separators go
+	expr := fmt.Sprintf("translate(lower(%s), '%s%s', '%s')", column, confusableFrom, separators, confusableTo)
+	for _, pair := range confusablePairs {
+		expr = fmt.Sprintf("replace(%s, '%s', '%s')", expr, pair[0], pair[1])
+	}
+	return expr
+}
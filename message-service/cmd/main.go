@@ -2,28 +2,37 @@ package main
 
 import (
 	"log"
-	"net/http"
 	"os"
 
+	"github.com/joy095/message-service/config/redis"
+	"github.com/joy095/message-service/controllers"
 	"github.com/joy095/message-service/db"
+	"github.com/joy095/message-service/hub"
 	"github.com/joy095/message-service/logger"
+	"github.com/joy095/message-service/presence"
+	"github.com/joy095/message-service/protocol"
+	"github.com/joy095/message-service/routes"
+	"github.com/joy095/message-service/utils"
+	"github.com/joy095/message-service/wordfilter"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla/websocket"
 	middleware "github.com/joy095/message-service/middlewares/cors"
+	goredis "github.com/redis/go-redis/v9"
 )
 
-var clients = make(map[*websocket.Conn]struct{})
-
-type Message struct {
-	From    string `json:"from"`
-	Message string `json:"message"`
-}
-
 func init() {
 	logger.InitLoggers()
 
 	db.Connect()
+
+	// Connect loads the environment, so the settings can be checked now
+	if err := wordfilter.ValidateConfig(); err != nil {
+		log.Fatalf("Invalid word filter configuration: %v", err)
+	}
+	// Every route and the WebSocket authenticate with it, so there's nothing to serve without it
+	if utils.GetJWTSecret() == nil {
+		log.Fatal("JWT_SECRET is not set")
+	}
 	// db.RunMigrations("db/schema.sql")
 }
 
@@ -33,81 +42,30 @@ func main() {
 		port = "8085"
 	}
 
+	// Replicas share deliveries through Redis; without it the service runs standalone
+	var rdb *goredis.Client
+	if os.Getenv("REDIS_HOST") != "" {
+		rdb = redis.GetRedisClient()
+		defer redis.CloseRedis()
+	}
+
+	// The hub owns every open WebSocket connection
+	h := hub.New(rdb)
+	go h.Run()
+
+	// Presence lives in Redis with a TTL, so a crashed replica's users time out
+	// and are reported offline by the replicas still running
+	tracker := presence.NewTracker(rdb, h)
+	go tracker.Run(func(p protocol.Presence) { controllers.BroadcastPresence(h, p) })
+
 	router := gin.Default()
 
 	router.Use(middleware.CorsMiddleware())
 
-	router.GET("/ws", serveWs)
+	routes.RegisterRoutes(router, h, tracker)
 
 	log.Println("Server starting on: " + port)
 	if err := router.Run(":" + port); err != nil {
 		log.Fatalf("Unable to start server: %v", err)
 	}
 }
-
-// var upgrader = websocket.Upgrader{
-// 	ReadBufferSize:  1024,
-// 	WriteBufferSize: 1024,
-// 	CheckOrigin: func(r *http.Request) bool {
-// 		allowed := os.Getenv("ALLOWED_ORIGINS")
-// 		origins := strings.Split(allowed, ",")
-// 		origin := r.Header.Get("Origin")
-
-// 		for _, o := range origins {
-// 			if strings.TrimSpace(o) == origin {
-// 				return true
-// 			}
-// 		}
-// 		return false
-// 	},
-// }
-
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // ⚠️ Accept all origins — only for dev/testing!
-	},
-}
-
-func serveWs(c *gin.Context) {
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
-	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
-		return
-	}
-
-	clients[conn] = struct{}{}
-	log.Println("Client connected")
-
-	go handleClient(conn)
-}
-
-func handleClient(conn *websocket.Conn) {
-	defer func() {
-		delete(clients, conn)
-		conn.Close()
-		log.Println("Client disconnected")
-	}()
-
-	for {
-		var msg Message
-		err := conn.ReadJSON(&msg)
-		if err != nil {
-			log.Printf("Read error: %v", err)
-			break
-		}
-		log.Printf("Received message: %+v", msg)
-		broadcast(msg)
-	}
-}
-
-func broadcast(msg Message) {
-	for conn := range clients {
-		if err := conn.WriteJSON(msg); err != nil {
-			log.Printf("Broadcast error: %v", err)
-			conn.Close()
-			delete(clients, conn)
-		}
-	}
-}
@@ -0,0 +1,516 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/joy095/message-service/db"
+	"github.com/joy095/message-service/hub"
+	"github.com/joy095/message-service/logger"
+	"github.com/joy095/message-service/models"
+	"github.com/joy095/message-service/protocol"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ConversationController handles conversation and membership requests
+type ConversationController struct {
+	hub *hub.Hub
+}
+
+// NewConversationController creates a new ConversationController that pushes live updates through h
+func NewConversationController(h *hub.Hub) *ConversationController {
+	return &ConversationController{hub: h}
+}
+
+// userIDParam returns the :user_id param, writing an error response and
+// returning ok=false if it isn't a UUID
+func userIDParam(c *gin.Context) (userID string, ok bool) {
+	userID = c.Param("user_id")
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return "", false
+	}
+	return userID, true
+}
+
+// conversationForMember loads the conversation in the :id param and the caller's
+// role in it, writing an error response and returning ok=false on failure
+func conversationForMember(c *gin.Context) (conv *models.Conversation, role string, ok bool) {
+	conversationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		return nil, "", false
+	}
+
+	conv, err = models.GetConversation(db.DB, conversationID)
+	if errors.Is(err, models.ErrConversationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Conversation not found"})
+		return nil, "", false
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to fetch conversation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conversation"})
+		return nil, "", false
+	}
+
+	role, err = models.GetParticipantRole(db.DB, conv.ID, c.GetString("user_id"))
+	if errors.Is(err, models.ErrNotParticipant) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not a participant of this conversation"})
+		return nil, "", false
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to fetch participant role: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conversation"})
+		return nil, "", false
+	}
+
+	return conv, role, true
+}
+
+// canManageMembers reports whether the caller may change a group's membership
+func canManageMembers(c *gin.Context, conv *models.Conversation, role string) bool {
+	isCreator := conv.CreatedBy != nil && *conv.CreatedBy == c.GetString("user_id")
+	return role == models.RoleAdmin || isCreator
+}
+
+// CreateDirectConversation opens (or returns the existing) 1:1 conversation with another user
+func (cc *ConversationController) CreateDirectConversation(c *gin.Context) {
+	logger.InfoLogger.Info("CreateDirectConversation handler called")
+
+	var req struct {
+		UserID string `json:"user_id" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if req.UserID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot start a conversation with yourself"})
+		return
+	}
+
+	err := models.CanMessageDirectly(db.DB, userID, req.UserID)
+	if errors.Is(err, models.ErrUsersBlocked) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot message this user"})
+		return
+	}
+	if errors.Is(err, models.ErrNotConnected) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only message accepted connections"})
+		return
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to check connection status: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create conversation"})
+		return
+	}
+
+	conv, created, err := models.CreateDirectConversation(db.DB, userID, req.UserID)
+	if errors.Is(err, models.ErrUnknownUser) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to create conversation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create conversation"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"conversation": conv})
+}
+
+// CreateGroupConversation creates a group with the caller as its admin
+func (cc *ConversationController) CreateGroupConversation(c *gin.Context) {
+	logger.InfoLogger.Info("CreateGroupConversation handler called")
+
+	var req struct {
+		Title          string   `json:"title" binding:"required,max=255"`
+		ParticipantIDs []string `json:"participant_ids" binding:"required,min=1,dive,uuid"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title must not be empty"})
+		return
+	}
+
+	conv, err := models.CreateGroupConversation(db.DB, c.GetString("user_id"), title, req.ParticipantIDs)
+	if errors.Is(err, models.ErrUnknownUser) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "One or more users not found"})
+		return
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to create group: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create group"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"conversation": conv})
+}
+
+// ListConversations returns the caller's conversations with their last message and unread count
+func (cc *ConversationController) ListConversations(c *gin.Context) {
+	logger.InfoLogger.Info("ListConversations handler called")
+
+	conversations, err := models.ListConversations(db.DB, c.GetString("user_id"))
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to list conversations: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conversations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"conversations": conversations})
+}
+
+// GetConversation returns a conversation and its participants
+func (cc *ConversationController) GetConversation(c *gin.Context) {
+	logger.InfoLogger.Info("GetConversation handler called")
+
+	conv, _, ok := conversationForMember(c)
+	if !ok {
+		return
+	}
+
+	participants, err := models.ListParticipants(db.DB, conv.ID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to list participants: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conversation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conversation": conv,
+		"participants": participants,
+	})
+}
+
+// RenameConversation changes a group's title
+func (cc *ConversationController) RenameConversation(c *gin.Context) {
+	logger.InfoLogger.Info("RenameConversation handler called")
+
+	var req struct {
+		Title string `json:"title" binding:"required,max=255"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title must not be empty"})
+		return
+	}
+
+	conv, _, ok := conversationForMember(c)
+	if !ok {
+		return
+	}
+
+	if !conv.IsGroup {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only group conversations can be renamed"})
+		return
+	}
+
+	if err := models.RenameConversation(db.DB, conv.ID, title); err != nil {
+		logger.ErrorLogger.Errorf("Failed to rename conversation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename conversation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Conversation renamed"})
+}
+
+// AddParticipants adds users to a group; only its creator or admins may do this
+func (cc *ConversationController) AddParticipants(c *gin.Context) {
+	logger.InfoLogger.Info("AddParticipants handler called")
+
+	var req struct {
+		UserIDs []string `json:"user_ids" binding:"required,min=1,dive,uuid"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conv, role, ok := conversationForMember(c)
+	if !ok {
+		return
+	}
+
+	if !conv.IsGroup {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Participants can only be added to groups"})
+		return
+	}
+
+	if !canManageMembers(c, conv, role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator or admins can add participants"})
+		return
+	}
+
+	err := models.AddParticipants(db.DB, conv.ID, req.UserIDs)
+	if errors.Is(err, models.ErrUnknownUser) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "One or more users not found"})
+		return
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to add participants: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add participants"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Participants added"})
+}
+
+// RemoveParticipant removes a user from a group; only its creator or admins may do this
+func (cc *ConversationController) RemoveParticipant(c *gin.Context) {
+	logger.InfoLogger.Info("RemoveParticipant handler called")
+
+	conv, role, ok := conversationForMember(c)
+	if !ok {
+		return
+	}
+
+	if !conv.IsGroup {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Participants can only be removed from groups"})
+		return
+	}
+
+	targetID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	userID := c.GetString("user_id")
+
+	if targetID != userID {
+		if !canManageMembers(c, conv, role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator or admins can remove participants"})
+			return
+		}
+
+		if conv.CreatedBy != nil && *conv.CreatedBy == targetID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "The group creator cannot be removed"})
+			return
+		}
+	}
+
+	err := models.RemoveParticipant(db.DB, conv.ID, targetID)
+	if errors.Is(err, models.ErrNotParticipant) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User is not a participant of this conversation"})
+		return
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to remove participant: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove participant"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Participant removed"})
+}
+
+// LeaveConversation removes the caller from a group
+func (cc *ConversationController) LeaveConversation(c *gin.Context) {
+	logger.InfoLogger.Info("LeaveConversation handler called")
+
+	conv, _, ok := conversationForMember(c)
+	if !ok {
+		return
+	}
+
+	if !conv.IsGroup {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot leave a direct conversation"})
+		return
+	}
+
+	if err := models.RemoveParticipant(db.DB, conv.ID, c.GetString("user_id")); err != nil {
+		logger.ErrorLogger.Errorf("Failed to leave conversation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave conversation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left conversation"})
+}
+
+// SetParticipantRole promotes a member to admin or demotes an admin; only the creator or admins may do this
+func (cc *ConversationController) SetParticipantRole(c *gin.Context) {
+	logger.InfoLogger.Info("SetParticipantRole handler called")
+
+	var req struct {
+		Role string `json:"role" binding:"required,oneof=admin member"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conv, role, ok := conversationForMember(c)
+	if !ok {
+		return
+	}
+
+	if !conv.IsGroup {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Roles only apply to groups"})
+		return
+	}
+
+	if !canManageMembers(c, conv, role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator or admins can change roles"})
+		return
+	}
+
+	targetID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	if conv.CreatedBy != nil && *conv.CreatedBy == targetID && targetID != c.GetString("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "The group creator's role cannot be changed"})
+		return
+	}
+
+	err := models.SetParticipantRole(db.DB, conv.ID, targetID, req.Role)
+	if errors.Is(err, models.ErrNotParticipant) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User is not a participant of this conversation"})
+		return
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to change role: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated"})
+}
+
+// MarkRead moves the caller's read marker forward so the conversation's unread count drops
+func (cc *ConversationController) MarkRead(c *gin.Context) {
+	logger.InfoLogger.Info("MarkRead handler called")
+
+	var req struct {
+		MessageID int `json:"message_id" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conv, _, ok := conversationForMember(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetString("user_id")
+
+	marker, err := models.MarkConversationRead(db.DB, conv.ID, userID, req.MessageID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to mark conversation read: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark conversation read"})
+		return
+	}
+
+	if marker > 0 {
+		sendReceipt(cc.hub, protocol.TypeReceiptRead, protocol.Receipt{ConversationID: conv.ID, UserID: userID, MessageID: marker})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Conversation marked as read"})
+}
+
+// canModerate reports whether the caller may change moderation settings or
+// review flagged messages: in groups the creator or admins, in direct
+// conversations either participant, though neither can turn the other's
+// protection off alone
+func canModerate(c *gin.Context, conv *models.Conversation, role string) bool {
+	return !conv.IsGroup || canManageMembers(c, conv, role)
+}
+
+// SetModerationPolicy chooses whether messages the word filter objects to are rejected, masked or flagged
+func (cc *ConversationController) SetModerationPolicy(c *gin.Context) {
+	logger.InfoLogger.Info("SetModerationPolicy handler called")
+
+	var req struct {
+		Policy string `json:"policy" binding:"required,oneof=reject mask flag"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conv, role, ok := conversationForMember(c)
+	if !ok {
+		return
+	}
+
+	if !canModerate(c, conv, role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator or admins can change the moderation policy"})
+		return
+	}
+
+	if conv.IsGroup {
+		if err := models.SetModerationPolicy(db.DB, conv.ID, req.Policy); err != nil {
+			logger.ErrorLogger.Errorf("Failed to change moderation policy: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change moderation policy"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Moderation policy updated"})
+		return
+	}
+
+	// Flagging delivers the other participant's messages unmasked, so in a
+	// direct conversation it waits until both participants ask for it
+	applied, err := models.SetDirectModerationPolicy(db.DB, conv.ID, c.GetString("user_id"), req.Policy)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to change moderation policy: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change moderation policy"})
+		return
+	}
+	if !applied {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Moderation policy will change once the other participant agrees"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Moderation policy updated"})
+}
+
+// GetFlaggedMessages lists messages delivered under the flag policy so they can be reviewed
+func (cc *ConversationController) GetFlaggedMessages(c *gin.Context) {
+	logger.InfoLogger.Info("GetFlaggedMessages handler called")
+
+	var query struct {
+		Limit int `form:"limit" binding:"omitempty,min=1"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conv, role, ok := conversationForMember(c)
+	if !ok {
+		return
+	}
+
+	if !canModerate(c, conv, role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator or admins can review flagged messages"})
+		return
+	}
+
+	messages, err := models.GetFlaggedMessages(db.DB, conv.ID, query.Limit)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to fetch flagged messages: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch flagged messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"messages": messages})
+}
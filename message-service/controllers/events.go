@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"github.com/joy095/message-service/db"
+	"github.com/joy095/message-service/hub"
+	"github.com/joy095/message-service/logger"
+	"github.com/joy095/message-service/models"
+	"github.com/joy095/message-service/protocol"
+)
+
+// sendToOthers pushes a server event to every participant of a conversation except userID
+func sendToOthers(h *hub.Hub, conversationID int, userID string, event protocol.Event) {
+	participants, err := models.GetConversationParticipants(db.DB, conversationID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to load participants for %s event: %v", event.Type, err)
+		return
+	}
+
+	recipients := make([]string, 0, len(participants))
+	for _, participant := range participants {
+		if participant != userID {
+			recipients = append(recipients, participant)
+		}
+	}
+
+	if err := h.SendToUsers(recipients, event); err != nil {
+		logger.ErrorLogger.Errorf("Failed to send %s event: %v", event.Type, err)
+	}
+}
+
+// sendReceipt tells a conversation's other participants how far a user has received or read
+func sendReceipt(h *hub.Hub, eventType string, receipt protocol.Receipt) {
+	sendToOthers(h, receipt.ConversationID, receipt.UserID, protocol.NewEvent(eventType, receipt))
+}
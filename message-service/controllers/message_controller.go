@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/joy095/message-service/db"
+	"github.com/joy095/message-service/logger"
+	"github.com/joy095/message-service/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MessageController handles message history requests
+type MessageController struct{}
+
+// NewMessageController creates a new MessageController
+func NewMessageController() *MessageController {
+	return &MessageController{}
+}
+
+// GetMessages returns a page of conversation history using keyset pagination
+// on the message ID (?before=<id> or ?after=<id>, plus optional ?limit=)
+func (mc *MessageController) GetMessages(c *gin.Context) {
+	logger.InfoLogger.Info("GetMessages handler called")
+
+	userID := c.GetString("user_id")
+
+	conversationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		return
+	}
+
+	var query struct {
+		Before int `form:"before" binding:"omitempty,min=1"`
+		After  int `form:"after" binding:"omitempty,min=1"`
+		Limit  int `form:"limit" binding:"omitempty,min=1"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if query.Before > 0 && query.After > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use either before or after, not both"})
+		return
+	}
+
+	isParticipant, err := models.IsParticipant(db.DB, conversationID, userID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to check participant: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch messages"})
+		return
+	}
+	if !isParticipant {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not a participant of this conversation"})
+		return
+	}
+
+	messages, hasMore, err := models.GetMessages(db.DB, conversationID, userID, query.Before, query.After, query.Limit)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to fetch messages: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages": messages,
+		"has_more": hasMore,
+	})
+}
+
+// messageForMember loads the message in the :id param, writing an error response
+// and returning ok=false unless the caller belongs to its conversation
+func messageForMember(c *gin.Context) (msg *models.Message, ok bool) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return nil, false
+	}
+
+	msg, err = models.GetMessage(db.DB, messageID)
+	if errors.Is(err, models.ErrMessageNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+		return nil, false
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to fetch message: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch message"})
+		return nil, false
+	}
+
+	isParticipant, err := models.IsParticipant(db.DB, msg.ConversationID, c.GetString("user_id"))
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to check participant: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch message"})
+		return nil, false
+	}
+	if !isParticipant {
+		// Don't reveal that the message exists
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+		return nil, false
+	}
+
+	return msg, true
+}
+
+// GetThread returns a message and every reply beneath it
+func (mc *MessageController) GetThread(c *gin.Context) {
+	logger.InfoLogger.Info("GetThread handler called")
+
+	msg, ok := messageForMember(c)
+	if !ok {
+		return
+	}
+
+	thread, err := models.GetThread(db.DB, msg.ID, c.GetString("user_id"))
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to fetch thread: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch thread"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"messages": thread})
+}
+
+// GetMessageEdits returns the previous versions of an edited message
+func (mc *MessageController) GetMessageEdits(c *gin.Context) {
+	logger.InfoLogger.Info("GetMessageEdits handler called")
+
+	msg, ok := messageForMember(c)
+	if !ok {
+		return
+	}
+
+	edits, err := models.GetMessageEdits(db.DB, msg.ID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to fetch edit history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch edit history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": msg,
+		"edits":   edits,
+	})
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/joy095/message-service/db"
+	"github.com/joy095/message-service/hub"
+	"github.com/joy095/message-service/logger"
+	"github.com/joy095/message-service/models"
+	"github.com/joy095/message-service/presence"
+	"github.com/joy095/message-service/protocol"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PresenceController handles presence lookups
+type PresenceController struct {
+	tracker *presence.Tracker
+}
+
+// NewPresenceController creates a new PresenceController
+func NewPresenceController(tracker *presence.Tracker) *PresenceController {
+	return &PresenceController{tracker: tracker}
+}
+
+// canSeePresence reports whether viewerID may see targetID's presence: users see
+// their own, and otherwise only accepted, unblocked connections see each other
+func canSeePresence(viewerID, targetID string) (bool, error) {
+	if viewerID == targetID {
+		return true, nil
+	}
+
+	status, err := models.GetConnectionStatus(db.DB, viewerID, targetID)
+	if err != nil {
+		return false, err
+	}
+	return status == models.ConnectionAccepted, nil
+}
+
+// BroadcastPresence tells a user's accepted connections that their status changed
+func BroadcastPresence(h *hub.Hub, p protocol.Presence) {
+	connections, err := models.ListAcceptedConnections(db.DB, p.UserID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to load connections for presence of %s: %v", p.UserID, err)
+		return
+	}
+
+	if err := h.SendToUsers(connections, protocol.NewEvent(protocol.TypePresence, p)); err != nil {
+		logger.ErrorLogger.Errorf("Failed to broadcast presence of %s: %v", p.UserID, err)
+	}
+}
+
+// GetPresence returns whether a connected user is online, or when they were last seen
+func (pc *PresenceController) GetPresence(c *gin.Context) {
+	logger.InfoLogger.Info("GetPresence handler called")
+
+	targetID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	visible, err := canSeePresence(c.GetString("user_id"), targetID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to check connection status: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch presence"})
+		return
+	}
+	if !visible {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only see the presence of your connections"})
+		return
+	}
+
+	p, err := pc.tracker.Get(targetID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to fetch presence: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch presence"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"presence": p})
+}
@@ -0,0 +1,551 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joy095/message-service/db"
+	"github.com/joy095/message-service/hub"
+	"github.com/joy095/message-service/logger"
+	"github.com/joy095/message-service/middlewares/auth"
+	"github.com/joy095/message-service/models"
+	"github.com/joy095/message-service/presence"
+	"github.com/joy095/message-service/protocol"
+	"github.com/joy095/message-service/utils"
+	"github.com/joy095/message-service/wordfilter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// Page size used when flushing undelivered messages to a reconnecting client
+const undeliveredBatchSize = 100
+
+// Maximum length of a client-generated event ID
+const maxEventIDLength = 64
+
+// Minimum time between typing.start, or typing.stop, relays for one user in one conversation
+const typingInterval = 3 * time.Second
+
+// var upgrader = websocket.Upgrader{
+// 	ReadBufferSize:  1024,
+// 	WriteBufferSize: 1024,
+// 	CheckOrigin: func(r *http.Request) bool {
+// 		allowed := os.Getenv("ALLOWED_ORIGINS")
+// 		origins := strings.Split(allowed, ",")
+// 		origin := r.Header.Get("Origin")
+
+// 		for _, o := range origins {
+// 			if strings.TrimSpace(o) == origin {
+// 				return true
+// 			}
+// 		}
+// 		return false
+// 	},
+// }
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return true // ⚠️ Accept all origins — only for dev/testing!
+	},
+}
+
+// typingLimiter throttles typing relays per user, conversation and event type.
+// Typing indicators are ephemeral, so this lives in memory only.
+type typingLimiter struct {
+	last map[string]time.Time
+	mu   sync.Mutex
+}
+
+// allow reports whether a typing.start, or a typing.stop if typing is false,
+// may be relayed now. Starts and stops are limited separately, so a stop right
+// after a start still gets through.
+func (l *typingLimiter) allow(userID string, conversationID int, typing bool) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	key := userID + ":" + strconv.Itoa(conversationID) + ":" + strconv.FormatBool(typing)
+	if last, ok := l.last[key]; ok && now.Sub(last) < typingInterval {
+		return false
+	}
+
+	// Forget stale entries so the map doesn't grow without bound
+	if len(l.last) > 10000 {
+		for k, t := range l.last {
+			if now.Sub(t) >= typingInterval {
+				delete(l.last, k)
+			}
+		}
+	}
+
+	l.last[key] = now
+	return true
+}
+
+// SocketController handles WebSocket connections
+type SocketController struct {
+	hub      *hub.Hub
+	presence *presence.Tracker
+	typing   *typingLimiter
+}
+
+// NewSocketController creates a new SocketController that delivers through h
+// and records connections in tracker
+func NewSocketController(h *hub.Hub, tracker *presence.Tracker) *SocketController {
+	return &SocketController{
+		hub:      h,
+		presence: tracker,
+		typing:   &typingLimiter{last: make(map[string]time.Time)},
+	}
+}
+
+// ServeWs authenticates the handshake, upgrades the connection and starts its pumps
+func (sc *SocketController) ServeWs(c *gin.Context) {
+	userID, err := auth.ValidateToken(auth.TokenFromRequest(c))
+	if err != nil {
+		logger.ErrorLogger.Errorf("WebSocket authentication failed: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+
+	client := hub.NewClient(sc.hub, conn, userID)
+	sc.hub.Register(client)
+	log.Printf("Client connected: %s", userID)
+
+	// The disconnect must not be recorded before the connect, or a socket that
+	// closes straight away would leave the user online for good
+	recorded := make(chan struct{})
+
+	go client.WritePump()
+	go func() {
+		client.ReadPump(sc.handleIncoming)
+		<-recorded
+		sc.disconnected(client)
+	}()
+	go sc.connected(client, recorded)
+}
+
+// connected announces a user who just came online and flushes what they
+// missed. It closes recorded once the connection's presence is recorded.
+func (sc *SocketController) connected(client *hub.Client, recorded chan<- struct{}) {
+	cameOnline, err := sc.presence.Connect(client)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to record presence for %s: %v", client.UserID, err)
+	}
+	if cameOnline {
+		BroadcastPresence(sc.hub, protocol.Presence{UserID: client.UserID, Status: presence.StatusOnline})
+	}
+	close(recorded)
+
+	sc.flushUndelivered(client)
+}
+
+// disconnected announces a user whose last connection just closed
+func (sc *SocketController) disconnected(client *hub.Client) {
+	wentOffline, lastSeen, err := sc.presence.Disconnect(client)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to record presence for %s: %v", client.UserID, err)
+		return
+	}
+	if wentOffline {
+		BroadcastPresence(sc.hub, protocol.Presence{UserID: client.UserID, Status: presence.StatusOffline, LastSeen: &lastSeen})
+	}
+}
+
+// flushUndelivered sends a reconnecting client everything that arrived while
+// the user was offline. Messages stay undelivered until the client acknowledges
+// them, so a client that drops mid-flush gets them again next time.
+func (sc *SocketController) flushUndelivered(client *hub.Client) {
+	afterID := 0
+	for {
+		messages, err := models.GetUndeliveredMessages(db.DB, client.UserID, afterID, undeliveredBatchSize)
+		if err != nil {
+			logger.ErrorLogger.Errorf("Failed to load undelivered messages for %s: %v", client.UserID, err)
+			return
+		}
+		if len(messages) == 0 {
+			return
+		}
+
+		// One event per page keeps the flush from overrunning the client's send queue
+		event := protocol.NewEvent(protocol.TypeMessagesUndelivered, messages)
+		if err := sc.hub.SendToClient(client, event); err != nil {
+			logger.ErrorLogger.Errorf("Failed to flush undelivered messages: %v", err)
+			return
+		}
+
+		if len(messages) < undeliveredBatchSize {
+			return
+		}
+		afterID = messages[len(messages)-1].ID
+	}
+}
+
+// handleIncoming decodes a client frame, dispatches it by type and answers with
+// an ack or an error carrying the frame's ID
+func (sc *SocketController) handleIncoming(client *hub.Client, data []byte) {
+	var envelope protocol.Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		sc.reply(client, protocol.Fail("", protocol.NewError(protocol.CodeInvalidFrame, "Frame is not a valid event envelope")))
+		return
+	}
+
+	log.Printf("Received %q event %q from %s", envelope.Type, envelope.ID, client.UserID)
+
+	result, perr := sc.dispatch(client, envelope)
+	if perr != nil {
+		sc.reply(client, protocol.Fail(envelope.ID, perr))
+		return
+	}
+	sc.reply(client, protocol.Ack(envelope.ID, result))
+}
+
+// dispatch runs the handler for an event and returns the ack payload
+func (sc *SocketController) dispatch(client *hub.Client, envelope protocol.Envelope) (any, *protocol.Error) {
+	if envelope.V > protocol.Version {
+		return nil, protocol.NewError(protocol.CodeUnsupportedVersion, "Protocol version is not supported")
+	}
+	if len(envelope.ID) > maxEventIDLength {
+		return nil, protocol.NewError(protocol.CodeInvalidFrame, "Event ID is too long")
+	}
+
+	switch envelope.Type {
+	case protocol.TypeMessageSend:
+		var payload protocol.SendMessage
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return sc.handleSend(client, envelope.ID, payload)
+
+	case protocol.TypeReceiptDelivered, protocol.TypeReceiptRead:
+		var payload protocol.Receipt
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return sc.handleReceipt(client, envelope.Type, payload)
+
+	case protocol.TypeTypingStart, protocol.TypeTypingStop:
+		var payload protocol.Typing
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return sc.handleTyping(client, envelope.Type == protocol.TypeTypingStart, payload)
+
+	case protocol.TypeMessageEdit:
+		var payload protocol.EditMessage
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return sc.handleEdit(client, payload)
+
+	case protocol.TypeMessageDelete:
+		var payload protocol.DeleteMessage
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return sc.handleDelete(client, payload)
+
+	case protocol.TypePresence:
+		var payload protocol.Presence
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return sc.handlePresence(client, payload)
+
+	default:
+		return nil, protocol.NewError(protocol.CodeUnknownType, "Unknown event type")
+	}
+}
+
+// reply sends an ack or error to the connection that sent the event
+func (sc *SocketController) reply(client *hub.Client, event protocol.Event) {
+	if err := sc.hub.SendToClient(client, event); err != nil {
+		logger.ErrorLogger.Errorf("Failed to reply to %s: %v", client.UserID, err)
+	}
+}
+
+func decodePayload(envelope protocol.Envelope, v any) *protocol.Error {
+	if len(envelope.Payload) == 0 {
+		return protocol.NewError(protocol.CodeInvalidPayload, "Payload is required")
+	}
+	if err := json.Unmarshal(envelope.Payload, v); err != nil {
+		return protocol.NewError(protocol.CodeInvalidPayload, "Payload is malformed")
+	}
+	return nil
+}
+
+// requireParticipant loads a conversation's participants and checks that userID is one of them
+func requireParticipant(conversationID int, userID string) ([]string, *protocol.Error) {
+	participants, err := models.GetConversationParticipants(db.DB, conversationID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to load participants for conversation %d: %v", conversationID, err)
+		return nil, protocol.NewError(protocol.CodeInternal, "Failed to load conversation")
+	}
+
+	if !slices.Contains(participants, userID) {
+		return nil, protocol.NewError(protocol.CodeForbidden, "You are not a participant of this conversation")
+	}
+	return participants, nil
+}
+
+// handleSend stores a message from the authenticated user and delivers it. The
+// sender always comes from the connection, never from the payload.
+func (sc *SocketController) handleSend(client *hub.Client, eventID string, payload protocol.SendMessage) (any, *protocol.Error) {
+	if strings.TrimSpace(payload.Content) == "" {
+		return nil, protocol.NewError(protocol.CodeInvalidPayload, "Message content must not be empty")
+	}
+
+	userID := client.UserID
+
+	participants, perr := requireParticipant(payload.ConversationID, userID)
+	if perr != nil {
+		return nil, perr
+	}
+
+	// Direct messages are only delivered between accepted, unblocked connections
+	conv, err := models.GetConversation(db.DB, payload.ConversationID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to load conversation %d: %v", payload.ConversationID, err)
+		return nil, protocol.NewError(protocol.CodeInternal, "Failed to load conversation")
+	}
+	if !conv.IsGroup {
+		for _, participant := range participants {
+			if participant == userID {
+				continue
+			}
+			if err := models.CanMessageDirectly(db.DB, userID, participant); err != nil {
+				logger.ErrorLogger.Errorf("Refusing direct message from %s to %s: %v", userID, participant, err)
+				return nil, protocol.NewError(protocol.CodeForbidden, "You can only message accepted connections")
+			}
+		}
+	}
+
+	content, flagged, merr := moderate(conv.ModerationPolicy, payload.Content)
+	if merr != nil {
+		return nil, merr
+	}
+
+	stored, created, err := models.CreateMessage(db.DB, payload.ConversationID, userID, content, payload.ReplyToMessageID, eventID, flagged)
+	if errors.Is(err, models.ErrInvalidReply) {
+		return nil, protocol.NewError(protocol.CodeInvalidPayload, "reply_to_message_id does not belong to this conversation")
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to store message: %v", err)
+		return nil, protocol.NewError(protocol.CodeInternal, "Failed to store message")
+	}
+
+	// A retried send was already delivered the first time
+	if created {
+		if err := sc.hub.SendToUsers(participants, protocol.NewEvent(protocol.TypeMessageNew, stored)); err != nil {
+			logger.ErrorLogger.Errorf("Failed to deliver message %d: %v", stored.ID, err)
+		}
+	}
+
+	return stored, nil
+}
+
+// moderate runs content through the word filter and applies the conversation's
+// policy. It returns the content to store, masked under the mask policy, and
+// whether the message should be flagged for review.
+func moderate(policy, content string) (moderated string, flagged bool, perr *protocol.Error) {
+	verdict, err := wordfilter.Check(content)
+	if err != nil {
+		if wordfilter.FailOpen() {
+			logger.ErrorLogger.Errorf("Word filter failed, delivering unchecked: %v", err)
+			return content, false, nil
+		}
+		logger.ErrorLogger.Errorf("Word filter failed, refusing message: %v", err)
+		return "", false, protocol.NewError(protocol.CodeModerationUnavailable, "Messages can't be checked right now, try again later")
+	}
+
+	if !verdict.ContainsBadWords {
+		return content, false, nil
+	}
+
+	switch policy {
+	case models.PolicyFlag:
+		return content, true, nil
+	case models.PolicyMask:
+		return verdict.Censored, false, nil
+	default:
+		return "", false, protocol.NewError(protocol.CodeContentRejected, "Message contains inappropriate words")
+	}
+}
+
+// messageError maps model errors from editing or deleting a message to protocol errors
+func messageError(err error, action string) *protocol.Error {
+	switch {
+	case errors.Is(err, models.ErrMessageNotFound):
+		return protocol.NewError(protocol.CodeNotFound, "Message not found")
+	case errors.Is(err, models.ErrNotSender):
+		return protocol.NewError(protocol.CodeForbidden, "Only the sender can "+action+" this message")
+	case errors.Is(err, models.ErrMessageDeleted):
+		return protocol.NewError(protocol.CodeForbidden, "Message has been deleted")
+	case errors.Is(err, models.ErrEditWindowExpired):
+		return protocol.NewError(protocol.CodeForbidden, "Message can no longer be edited")
+	default:
+		logger.ErrorLogger.Errorf("Failed to %s message: %v", action, err)
+		return protocol.NewError(protocol.CodeInternal, "Failed to "+action+" message")
+	}
+}
+
+// handleEdit changes a message's content and pushes the new version to the conversation
+func (sc *SocketController) handleEdit(client *hub.Client, payload protocol.EditMessage) (any, *protocol.Error) {
+	if strings.TrimSpace(payload.Content) == "" {
+		return nil, protocol.NewError(protocol.CodeInvalidPayload, "Message content must not be empty")
+	}
+
+	msg, err := models.GetMessage(db.DB, payload.MessageID)
+	if err != nil {
+		return nil, messageError(err, "edit")
+	}
+
+	participants, perr := requireParticipant(msg.ConversationID, client.UserID)
+	if perr != nil {
+		// Don't reveal that the message exists
+		return nil, messageError(models.ErrMessageNotFound, "edit")
+	}
+
+	conv, err := models.GetConversation(db.DB, msg.ConversationID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to load conversation %d: %v", msg.ConversationID, err)
+		return nil, protocol.NewError(protocol.CodeInternal, "Failed to load conversation")
+	}
+
+	content, flagged, merr := moderate(conv.ModerationPolicy, payload.Content)
+	if merr != nil {
+		return nil, merr
+	}
+
+	edited, err := models.EditMessage(db.DB, msg.ID, client.UserID, content, utils.GetMessageEditWindow(), flagged)
+	if err != nil {
+		return nil, messageError(err, "edit")
+	}
+
+	if err := sc.hub.SendToUsers(participants, protocol.NewEvent(protocol.TypeMessageEdited, edited)); err != nil {
+		logger.ErrorLogger.Errorf("Failed to deliver edit of message %d: %v", edited.ID, err)
+	}
+	return edited, nil
+}
+
+// handleDelete deletes a message for everyone, leaving a tombstone, or hides it
+// from the caller's own history. Either way the change is pushed live: to every
+// participant, or only to the caller's other devices.
+func (sc *SocketController) handleDelete(client *hub.Client, payload protocol.DeleteMessage) (any, *protocol.Error) {
+	msg, err := models.GetMessage(db.DB, payload.MessageID)
+	if err != nil {
+		return nil, messageError(err, "delete")
+	}
+
+	participants, perr := requireParticipant(msg.ConversationID, client.UserID)
+	if perr != nil {
+		// Don't reveal that the message exists
+		return nil, messageError(models.ErrMessageNotFound, "delete")
+	}
+
+	recipients := []string{client.UserID}
+	if payload.ForEveryone {
+		if _, err := models.DeleteMessageForEveryone(db.DB, msg.ID, client.UserID); err != nil {
+			return nil, messageError(err, "delete")
+		}
+		recipients = participants
+	} else if err := models.HideMessage(db.DB, msg.ID, client.UserID); err != nil {
+		return nil, messageError(err, "delete")
+	}
+
+	deleted := protocol.MessageDeleted{
+		MessageID:      msg.ID,
+		ConversationID: msg.ConversationID,
+		ForEveryone:    payload.ForEveryone,
+	}
+	if err := sc.hub.SendToUsers(recipients, protocol.NewEvent(protocol.TypeMessageDeleted, deleted)); err != nil {
+		logger.ErrorLogger.Errorf("Failed to deliver deletion of message %d: %v", msg.ID, err)
+	}
+	return deleted, nil
+}
+
+// handleReceipt moves the user's delivery or read marker and tells the other participants
+func (sc *SocketController) handleReceipt(client *hub.Client, eventType string, payload protocol.Receipt) (any, *protocol.Error) {
+	if payload.MessageID <= 0 {
+		return nil, protocol.NewError(protocol.CodeInvalidPayload, "message_id is required")
+	}
+
+	var (
+		marker int
+		err    error
+	)
+	if eventType == protocol.TypeReceiptRead {
+		marker, err = models.MarkConversationRead(db.DB, payload.ConversationID, client.UserID, payload.MessageID)
+	} else {
+		marker, err = models.MarkConversationDelivered(db.DB, payload.ConversationID, client.UserID, payload.MessageID)
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to record %s for %s: %v", eventType, client.UserID, err)
+		return nil, protocol.NewError(protocol.CodeInternal, "Failed to record receipt")
+	}
+
+	if marker > 0 {
+		sendReceipt(sc.hub, eventType, protocol.Receipt{ConversationID: payload.ConversationID, UserID: client.UserID, MessageID: marker})
+	}
+	return nil, nil
+}
+
+// handleTyping relays a typing indicator to the conversation's other participants.
+// Repeated typing events within typingInterval are acked but not relayed, and
+// are dropped before the database is asked whether the user may send them.
+func (sc *SocketController) handleTyping(client *hub.Client, typing bool, payload protocol.Typing) (any, *protocol.Error) {
+	if !sc.typing.allow(client.UserID, payload.ConversationID, typing) {
+		return nil, nil
+	}
+
+	if _, perr := requireParticipant(payload.ConversationID, client.UserID); perr != nil {
+		return nil, perr
+	}
+
+	event := protocol.NewEvent(protocol.TypeTyping, protocol.Typing{
+		ConversationID: payload.ConversationID,
+		UserID:         client.UserID,
+		Typing:         typing,
+	})
+	sendToOthers(sc.hub, payload.ConversationID, client.UserID, event)
+	return nil, nil
+}
+
+// handlePresence answers a presence query for one of the user's connections
+func (sc *SocketController) handlePresence(client *hub.Client, payload protocol.Presence) (any, *protocol.Error) {
+	if payload.UserID == "" {
+		return nil, protocol.NewError(protocol.CodeInvalidPayload, "user_id is required")
+	}
+
+	visible, err := canSeePresence(client.UserID, payload.UserID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to check connection status: %v", err)
+		return nil, protocol.NewError(protocol.CodeInternal, "Failed to fetch presence")
+	}
+	if !visible {
+		return nil, protocol.NewError(protocol.CodeForbidden, "You can only see the presence of your connections")
+	}
+
+	p, err := sc.presence.Get(payload.UserID)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to fetch presence: %v", err)
+		return nil, protocol.NewError(protocol.CodeInternal, "Failed to fetch presence")
+	}
+	return p, nil
+}
@@ -23,12 +23,19 @@ CREATE TABLE conversations (
     is_group BOOLEAN DEFAULT FALSE,
     title VARCHAR(255), -- optional for groups
     created_by UUID  REFERENCES users(id),
+    -- what happens to messages word_filter_service objects to: 'reject', 'mask' or 'flag'
+    moderation_policy VARCHAR(10) NOT NULL DEFAULT 'reject',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 CREATE TABLE conversation_participants (
     conversation_id INTEGER REFERENCES conversations(id) ON DELETE CASCADE,
     user_id UUID  REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'member', -- 'admin' or 'member'
+    last_delivered_message_id INTEGER, -- everything up to here has reached one of the user's devices
+    last_read_message_id INTEGER,
+    -- in a direct conversation, the 'flag' policy only applies once every participant agrees to it
+    agrees_to_flag BOOLEAN NOT NULL DEFAULT FALSE,
     joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 
     PRIMARY KEY (conversation_id, user_id)
@@ -40,6 +47,28 @@ CREATE TABLE messages (
     sender_id UUID REFERENCES users(id) ON DELETE CASCADE,
     content TEXT NOT NULL,
     reply_to_message_id INTEGER REFERENCES messages(id),
+    client_message_id VARCHAR(64), -- client-generated event ID, makes message.send retries idempotent
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    edited_at TIMESTAMP
+    edited_at TIMESTAMP,
+    deleted_at TIMESTAMP, -- set when deleted for everyone; content is cleared
+    flagged BOOLEAN NOT NULL DEFAULT FALSE, -- delivered under the 'flag' policy, awaiting review
+
+    UNIQUE (sender_id, client_message_id)
+);
+
+-- Previous versions of edited messages
+CREATE TABLE message_edits (
+    id SERIAL PRIMARY KEY,
+    message_id INTEGER REFERENCES messages(id) ON DELETE CASCADE,
+    previous_content TEXT NOT NULL,
+    edited_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Messages a user has deleted from their own view only
+CREATE TABLE hidden_messages (
+    message_id INTEGER REFERENCES messages(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    hidden_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (message_id, user_id)
 );
\ No newline at end of file
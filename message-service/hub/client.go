@@ -0,0 +1,104 @@
+package hub
+
+import (
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Time allowed to write a message to the peer
+	writeWait = 10 * time.Second
+
+	// Time allowed to read the next pong message from the peer
+	pongWait = 60 * time.Second
+
+	// Send pings to the peer with this period. Must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+
+	// Maximum message size allowed from the peer
+	maxMessageSize = 16 * 1024
+
+	// Number of outbound payloads buffered per connection before it counts as slow
+	sendBufferSize = 256
+)
+
+// Client is a single WebSocket connection belonging to an authenticated user
+type Client struct {
+	UserID string
+
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// NewClient wraps an upgraded connection for the given user
+func NewClient(h *Hub, conn *websocket.Conn, userID string) *Client {
+	return &Client{
+		UserID: userID,
+		hub:    h,
+		conn:   conn,
+		send:   make(chan []byte, sendBufferSize),
+	}
+}
+
+// ReadPump reads inbound frames and passes each one to onMessage. It is the only
+// reader of the connection and unregisters the client when the peer goes away.
+func (c *Client) ReadPump(onMessage func(c *Client, data []byte)) {
+	defer func() {
+		c.hub.Unregister(c)
+		c.conn.Close()
+		log.Printf("Client disconnected: %s", c.UserID)
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("Read error: %v", err)
+			}
+			return
+		}
+		onMessage(c, data)
+	}
+}
+
+// WritePump drains the client's send queue and keeps the connection alive with
+// pings. It is the only writer of the connection.
+func (c *Client) WritePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// The hub closed the queue
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				log.Printf("Write error: %v", err)
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package hub
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/joy095/message-service/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Every replica subscribes to the channels of the users connected to it, so a
+// payload published for a user reaches whichever replicas hold their sockets
+const userChannelPrefix = "message-service:user:"
+
+func userChannel(userID string) string {
+	return userChannelPrefix + userID
+}
+
+// fanout relays deliveries between message-service replicas over Redis pub/sub
+type fanout struct {
+	hub    *Hub
+	rdb    *redis.Client
+	pubsub *redis.PubSub
+
+	// pending holds users whose subscription may need to change. It is drained by
+	// reconcile so the hub's Run loop never waits on Redis.
+	pending map[string]struct{}
+	// waiters holds, for each user, the channels to close once Redis confirms
+	// the user's subscription
+	waiters   map[string][]chan struct{}
+	pendingMu sync.Mutex
+	wake      chan struct{}
+}
+
+func newFanout(h *Hub, rdb *redis.Client) *fanout {
+	return &fanout{
+		hub:     h,
+		rdb:     rdb,
+		pubsub:  rdb.Subscribe(context.Background()),
+		pending: make(map[string]struct{}),
+		waiters: make(map[string][]chan struct{}),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// run starts the subscriber and subscription workers
+func (f *fanout) run() {
+	go f.listen()
+	go f.reconcile()
+}
+
+// publish sends a payload to every replica subscribed to the given users
+func (f *fanout) publish(userIDs []string, payload []byte) error {
+	_, err := f.rdb.Pipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		for _, userID := range userIDs {
+			pipe.Publish(context.Background(), userChannel(userID), payload)
+		}
+		return nil
+	})
+	return err
+}
+
+// listen hands every payload received from Redis to the hub for local
+// delivery, and releases the registrations waiting on each confirmed subscription
+func (f *fanout) listen() {
+	for msg := range f.pubsub.ChannelWithSubscriptions() {
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			if msg.Kind == "subscribe" {
+				f.release(strings.TrimPrefix(msg.Channel, userChannelPrefix))
+			}
+		case *redis.Message:
+			userID := strings.TrimPrefix(msg.Channel, userChannelPrefix)
+			f.hub.deliveries <- delivery{userIDs: []string{userID}, payload: []byte(msg.Payload)}
+		}
+	}
+}
+
+// touch marks a user's subscription for reconciling after they connect or
+// disconnect. subscribed, if not nil, is closed once the user's channel is
+// subscribed, or once subscribing has failed.
+func (f *fanout) touch(userID string, subscribed chan struct{}) {
+	f.pendingMu.Lock()
+	f.pending[userID] = struct{}{}
+	if subscribed != nil {
+		f.waiters[userID] = append(f.waiters[userID], subscribed)
+	}
+	f.pendingMu.Unlock()
+
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// release closes the channels waiting on a user's subscription
+func (f *fanout) release(userIDs ...string) {
+	f.pendingMu.Lock()
+	defer f.pendingMu.Unlock()
+
+	for _, userID := range userIDs {
+		for _, subscribed := range f.waiters[userID] {
+			close(subscribed)
+		}
+		delete(f.waiters, userID)
+	}
+}
+
+// reconcile subscribes to users who are connected here and unsubscribes from
+// users who are not. Comparing against the hub's current state, rather than
+// replaying connect/disconnect events, keeps it correct however events interleave.
+func (f *fanout) reconcile() {
+	for range f.wake {
+		f.pendingMu.Lock()
+		users := f.pending
+		f.pending = make(map[string]struct{})
+		f.pendingMu.Unlock()
+
+		var subscribe, unsubscribe, subscribing, gone []string
+		for userID := range users {
+			if f.hub.IsOnline(userID) {
+				subscribe = append(subscribe, userChannel(userID))
+				subscribing = append(subscribing, userID)
+			} else {
+				unsubscribe = append(unsubscribe, userChannel(userID))
+				gone = append(gone, userID)
+			}
+		}
+
+		// Nobody waits on a user who already disconnected
+		f.release(gone...)
+
+		if len(subscribe) > 0 {
+			// Redis confirms each channel, even one already subscribed, and
+			// listen releases the user's registrations when it does
+			if err := f.pubsub.Subscribe(context.Background(), subscribe...); err != nil {
+				logger.ErrorLogger.Errorf("Failed to subscribe to user channels: %v", err)
+				f.release(subscribing...)
+			}
+		}
+		if len(unsubscribe) > 0 {
+			if err := f.pubsub.Unsubscribe(context.Background(), unsubscribe...); err != nil {
+				logger.ErrorLogger.Errorf("Failed to unsubscribe from user channels: %v", err)
+			}
+		}
+	}
+}
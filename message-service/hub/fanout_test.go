@@ -0,0 +1,103 @@
+package hub
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// startReplica runs a hub that fans out through the given Redis server, as
+// one replica of message-service would
+func startReplica(t *testing.T, mr *miniredis.Miniredis) *Hub {
+	t.Helper()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return startHub(t, rdb)
+}
+
+func subscribers(mr *miniredis.Miniredis, userID string) int {
+	return mr.PubSubNumSub(userChannel(userID))[userChannel(userID)]
+}
+
+func TestFanoutAcrossReplicas(t *testing.T) {
+	mr := miniredis.RunT(t)
+	sender := startReplica(t, mr)
+	receiver := startReplica(t, mr)
+
+	alice := newTestClient(receiver, "alice", sendBufferSize)
+	receiver.Register(alice)
+	eventually(t, func() bool { return subscribers(mr, "alice") == 1 }, "receiving replica did not subscribe to alice's channel")
+
+	if err := sender.SendToUsers([]string{"alice"}, "hello"); err != nil {
+		t.Fatalf("SendToUsers: %v", err)
+	}
+
+	select {
+	case payload := <-alice.send:
+		if string(payload) != `"hello"` {
+			t.Errorf("alice got %s, want \"hello\"", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("payload published on one replica never reached the other")
+	}
+
+	// A second device keeps the subscription until both have gone
+	phone := newTestClient(receiver, "alice", sendBufferSize)
+	receiver.Register(phone)
+	receiver.Unregister(alice)
+	time.Sleep(50 * time.Millisecond)
+	if subscribers(mr, "alice") != 1 {
+		t.Fatal("replica unsubscribed while alice still had a client connected")
+	}
+
+	receiver.Unregister(phone)
+	eventually(t, func() bool { return subscribers(mr, "alice") == 0 }, "replica stayed subscribed after alice's last client disconnected")
+}
+
+func TestSendRightAfterConnecting(t *testing.T) {
+	mr := miniredis.RunT(t)
+	sender := startReplica(t, mr)
+	receiver := startReplica(t, mr)
+
+	// Once Register returns, the subscription is live, so nothing sent from
+	// here on is lost to the connection
+	for i := range 20 {
+		userID := fmt.Sprintf("user-%d", i)
+		client := newTestClient(receiver, userID, sendBufferSize)
+		receiver.Register(client)
+		if subscribers(mr, userID) != 1 {
+			t.Fatalf("Register returned before %s's channel was subscribed", userID)
+		}
+		if err := sender.SendToUsers([]string{userID}, i); err != nil {
+			t.Fatalf("SendToUsers: %v", err)
+		}
+
+		select {
+		case payload := <-client.send:
+			if string(payload) != strconv.Itoa(i) {
+				t.Errorf("%s got %s, want %d", userID, payload, i)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("payload sent right after %s connected never arrived", userID)
+		}
+	}
+}
+
+func TestRegisterWithoutRedisDoesNotWait(t *testing.T) {
+	h := startHub(t, nil)
+
+	done := make(chan struct{})
+	go func() {
+		h.Register(newTestClient(h, "alice", sendBufferSize))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Register waited for a subscription without Redis")
+	}
+}
@@ -0,0 +1,213 @@
+package hub
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/joy095/message-service/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// subscribeTimeout bounds how long Register waits for Redis to confirm a
+// user's subscription before letting the connection go ahead anyway
+const subscribeTimeout = 5 * time.Second
+
+// registration is a client joining the hub. subscribed is closed once payloads
+// published for the client's user, from any replica, reach this hub.
+type registration struct {
+	client     *Client
+	subscribed chan struct{}
+}
+
+// delivery is a payload queued for a set of users, or for a single client
+type delivery struct {
+	userIDs []string
+	client  *Client
+	payload []byte
+}
+
+// Hub tracks every connected client by user ID and fans payloads out to them.
+// Registration, unregistration and delivery all go through the Run loop, so a
+// client's send channel is only ever written to or closed from one goroutine.
+type Hub struct {
+	// clients maps a user ID to every connection that user has open, one per device
+	clients map[string]map[*Client]struct{}
+	mu      sync.RWMutex
+
+	register   chan registration
+	unregister chan *Client
+	deliveries chan delivery
+
+	// fanout is nil when running as a single replica without Redis
+	fanout *fanout
+}
+
+// New creates a Hub. With a Redis client, deliveries are fanned out to every
+// replica through pub/sub; with nil, only local connections are reached.
+// Call Run in its own goroutine before registering clients.
+func New(rdb *redis.Client) *Hub {
+	h := &Hub{
+		clients:    make(map[string]map[*Client]struct{}),
+		register:   make(chan registration),
+		unregister: make(chan *Client),
+		deliveries: make(chan delivery, 256),
+	}
+
+	if rdb != nil {
+		h.fanout = newFanout(h, rdb)
+	}
+
+	return h
+}
+
+// Run processes registrations and deliveries until the process exits
+func (h *Hub) Run() {
+	if h.fanout != nil {
+		h.fanout.run()
+	}
+
+	for {
+		select {
+		case r := <-h.register:
+			client := r.client
+			h.mu.Lock()
+			if h.clients[client.UserID] == nil {
+				h.clients[client.UserID] = make(map[*Client]struct{})
+			}
+			h.clients[client.UserID][client] = struct{}{}
+			h.mu.Unlock()
+
+			if h.fanout != nil {
+				h.fanout.touch(client.UserID, r.subscribed)
+			} else {
+				close(r.subscribed)
+			}
+
+		case client := <-h.unregister:
+			h.remove(client)
+
+		case d := <-h.deliveries:
+			if d.client != nil {
+				// The client may have gone away since the payload was queued
+				if h.isRegistered(d.client) {
+					h.enqueue(d.client, d.payload)
+				}
+				continue
+			}
+
+			for _, userID := range d.userIDs {
+				h.mu.RLock()
+				targets := make([]*Client, 0, len(h.clients[userID]))
+				for client := range h.clients[userID] {
+					targets = append(targets, client)
+				}
+				h.mu.RUnlock()
+
+				for _, client := range targets {
+					h.enqueue(client, d.payload)
+				}
+			}
+		}
+	}
+}
+
+// enqueue hands a payload to a client's writer without blocking. A client whose
+// queue is full is too slow to keep up and gets disconnected.
+func (h *Hub) enqueue(client *Client, payload []byte) {
+	select {
+	case client.send <- payload:
+	default:
+		logger.ErrorLogger.Errorf("Disconnecting slow client for user %s", client.UserID)
+		h.remove(client)
+	}
+}
+
+// isRegistered reports whether a client is still connected to this hub
+func (h *Hub) isRegistered(client *Client) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	_, ok := h.clients[client.UserID][client]
+	return ok
+}
+
+// remove forgets a client and closes its send queue, which stops its writer
+func (h *Hub) remove(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[client.UserID][client]; !ok {
+		return
+	}
+
+	delete(h.clients[client.UserID], client)
+	if len(h.clients[client.UserID]) == 0 {
+		delete(h.clients, client.UserID)
+
+		if h.fanout != nil {
+			h.fanout.touch(client.UserID, nil)
+		}
+	}
+	close(client.send)
+}
+
+// Register adds a client to the hub. It returns once payloads published for the
+// client's user on any replica reach it, so whatever the caller sends after,
+// such as a flush of missed messages, can't leave a gap.
+func (h *Hub) Register(client *Client) {
+	subscribed := make(chan struct{})
+	h.register <- registration{client: client, subscribed: subscribed}
+
+	select {
+	case <-subscribed:
+	case <-time.After(subscribeTimeout):
+		logger.ErrorLogger.Errorf("Timed out waiting for the subscription of user %s", client.UserID)
+	}
+}
+
+// Unregister removes a client from the hub
+func (h *Hub) Unregister(client *Client) {
+	h.unregister <- client
+}
+
+// SendToUsers delivers v, encoded as JSON, to every connected device of each
+// user, on this replica or any other
+func (h *Hub) SendToUsers(userIDs []string, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if h.fanout != nil {
+		err := h.fanout.publish(userIDs, payload)
+		if err == nil {
+			return nil
+		}
+		// Reach at least the users connected here while Redis is unavailable
+		logger.ErrorLogger.Errorf("Failed to publish delivery, delivering locally only: %v", err)
+	}
+
+	h.deliveries <- delivery{userIDs: userIDs, payload: payload}
+	return nil
+}
+
+// SendToClient delivers v, encoded as JSON, to a single connection
+func (h *Hub) SendToClient(client *Client, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	h.deliveries <- delivery{client: client, payload: payload}
+	return nil
+}
+
+// IsOnline reports whether a user has at least one open connection
+func (h *Hub) IsOnline(userID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.clients[userID]) > 0
+}
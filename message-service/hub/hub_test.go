@@ -0,0 +1,232 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joy095/message-service/logger"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+func TestMain(m *testing.M) {
+	// The hub logs evictions; keep test runs from writing log files
+	discard := logrus.New()
+	discard.SetOutput(io.Discard)
+	logger.InfoLogger = logrus.NewEntry(discard)
+	logger.ErrorLogger = logrus.NewEntry(discard)
+
+	os.Exit(m.Run())
+}
+
+// newTestClient makes a client without a connection, whose queue the test drains itself
+func newTestClient(h *Hub, userID string, buffer int) *Client {
+	return &Client{UserID: userID, hub: h, send: make(chan []byte, buffer)}
+}
+
+// startHub runs a hub, fanning out through rdb unless it's nil
+func startHub(t *testing.T, rdb *redis.Client) *Hub {
+	t.Helper()
+	h := New(rdb)
+	go h.Run()
+	return h
+}
+
+// eventually fails the test if condition doesn't hold within a few seconds
+func eventually(t *testing.T, condition func() bool, format string, args ...any) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf(format, args...)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestConcurrentDelivery(t *testing.T) {
+	const (
+		users    = 20
+		devices  = 3
+		messages = 50
+	)
+	h := startHub(t, nil)
+
+	clients := make([]*Client, 0, users*devices)
+	for u := range users {
+		for range devices {
+			clients = append(clients, newTestClient(h, fmt.Sprintf("user-%d", u), sendBufferSize))
+		}
+	}
+
+	// Each client expects its user's messages plus one addressed to it alone
+	want := messages + 1
+	var received sync.WaitGroup
+	counts := make([]int, len(clients))
+	for i, client := range clients {
+		received.Add(1)
+		go func() {
+			for range client.send {
+				counts[i]++
+				if counts[i] == want {
+					received.Done()
+				}
+			}
+		}()
+	}
+
+	var registered sync.WaitGroup
+	for _, client := range clients {
+		registered.Add(1)
+		go func() {
+			defer registered.Done()
+			h.Register(client)
+		}()
+	}
+	registered.Wait()
+
+	var senders sync.WaitGroup
+	for u := range users {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for n := range messages {
+				if err := h.SendToUsers([]string{fmt.Sprintf("user-%d", u)}, n); err != nil {
+					t.Errorf("SendToUsers: %v", err)
+				}
+			}
+		}()
+	}
+	for _, client := range clients {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			if err := h.SendToClient(client, "direct"); err != nil {
+				t.Errorf("SendToClient: %v", err)
+			}
+		}()
+	}
+	senders.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		received.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("not every client received %d payloads: %v", want, counts)
+	}
+
+	for u := range users {
+		if !h.IsOnline(fmt.Sprintf("user-%d", u)) {
+			t.Errorf("user-%d is not online", u)
+		}
+	}
+}
+
+func TestConcurrentUnregisterWhileSending(t *testing.T) {
+	const clientsPerUser = 10
+	h := startHub(t, nil)
+
+	var clients []*Client
+	for u := range 5 {
+		for range clientsPerUser {
+			client := newTestClient(h, fmt.Sprintf("user-%d", u), sendBufferSize)
+			h.Register(client)
+			clients = append(clients, client)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		// Drain until the hub closes the queue
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range client.send {
+			}
+		}()
+	}
+
+	stop := make(chan struct{})
+	var senders sync.WaitGroup
+	for u := range 5 {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				h.SendToUsers([]string{fmt.Sprintf("user-%d", u)}, "payload")
+			}
+		}()
+	}
+
+	for _, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.SendToClient(client, "direct")
+			h.Unregister(client)
+			// Unregistering twice, or sending after, must be harmless
+			h.Unregister(client)
+			h.SendToClient(client, "late")
+		}()
+	}
+
+	wg.Wait()
+	close(stop)
+	senders.Wait()
+
+	for u := range 5 {
+		if h.IsOnline(fmt.Sprintf("user-%d", u)) {
+			t.Errorf("user-%d is still online after every client unregistered", u)
+		}
+	}
+}
+
+func TestSlowClientIsEvicted(t *testing.T) {
+	h := startHub(t, nil)
+
+	slow := newTestClient(h, "slow", 1)
+	fast := newTestClient(h, "fast", sendBufferSize)
+	h.Register(slow)
+	h.Register(fast)
+
+	for n := range 3 {
+		if err := h.SendToUsers([]string{"slow", "fast"}, n); err != nil {
+			t.Fatalf("SendToUsers: %v", err)
+		}
+	}
+
+	eventually(t, func() bool { return !h.IsOnline("slow") }, "slow client was not disconnected")
+
+	// The queued payload is still there, then the closed queue tells the writer to stop
+	var got []int
+	for payload := range slow.send {
+		var n int
+		if err := json.Unmarshal(payload, &n); err != nil {
+			t.Fatalf("unexpected payload %q", payload)
+		}
+		got = append(got, n)
+	}
+	if len(got) != 1 || got[0] != 0 {
+		t.Errorf("slow client got %v, want [0]", got)
+	}
+
+	if !h.IsOnline("fast") {
+		t.Fatal("fast client was disconnected along with the slow one")
+	}
+	eventually(t, func() bool { return len(fast.send) == 3 }, "fast client did not get all 3 payloads")
+}
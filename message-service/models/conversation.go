@@ -0,0 +1,505 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Participant roles
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// Moderation policies, deciding what happens to a message the word filter objects to
+const (
+	PolicyReject = "reject" // refuse the message
+	PolicyMask   = "mask"   // deliver it with the offending words masked
+	PolicyFlag   = "flag"   // deliver it unchanged and flag it for review
+)
+
+var (
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrNotParticipant       = errors.New("user is not a participant of this conversation")
+	ErrUnknownUser          = errors.New("one or more users do not exist")
+)
+
+// Conversation Model
+type Conversation struct {
+	ID               int       `json:"id"`
+	IsGroup          bool      `json:"is_group"`
+	Title            *string   `json:"title"`
+	CreatedBy        *string   `json:"created_by"`
+	ModerationPolicy string    `json:"moderation_policy"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Participant is a member of a conversation
+type Participant struct {
+	UserID   string    `json:"user_id"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// ConversationSummary is a conversation as shown in a user's inbox
+type ConversationSummary struct {
+	Conversation
+	Participants []string `json:"participants"`
+	LastMessage  *Message `json:"last_message"`
+	UnreadCount  int      `json:"unread_count"`
+}
+
+const conversationColumns = `id, COALESCE(is_group, FALSE), title, created_by, moderation_policy, created_at`
+
+func scanConversation(row pgx.Row) (*Conversation, error) {
+	var conv Conversation
+	err := row.Scan(&conv.ID, &conv.IsGroup, &conv.Title, &conv.CreatedBy, &conv.ModerationPolicy, &conv.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+// translateUserError maps foreign key violations on user IDs to ErrUnknownUser
+func translateUserError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return ErrUnknownUser
+	}
+	return err
+}
+
+// GetConversation retrieves a conversation by ID
+func GetConversation(db *pgxpool.Pool, conversationID int) (*Conversation, error) {
+	return scanConversation(db.QueryRow(context.Background(),
+		`SELECT `+conversationColumns+` FROM conversations WHERE id = $1`, conversationID))
+}
+
+// CreateDirectConversation returns the 1:1 conversation between two users,
+// creating it if it does not exist yet. created reports whether a new one was made.
+func CreateDirectConversation(db *pgxpool.Pool, userID, otherUserID string) (conv *Conversation, created bool, err error) {
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize creation per user pair so concurrent requests can't create duplicates
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext(LEAST($1, $2) || GREATEST($1, $2)))`, userID, otherUserID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	conv, err = scanConversation(tx.QueryRow(ctx, `
+		SELECT `+conversationColumns+` FROM conversations c
+		WHERE NOT COALESCE(c.is_group, FALSE)
+		  AND EXISTS(SELECT 1 FROM conversation_participants WHERE conversation_id = c.id AND user_id = $1)
+		  AND EXISTS(SELECT 1 FROM conversation_participants WHERE conversation_id = c.id AND user_id = $2)
+		LIMIT 1
+	`, userID, otherUserID))
+	if err == nil {
+		return conv, false, nil
+	}
+	if !errors.Is(err, ErrConversationNotFound) {
+		return nil, false, err
+	}
+
+	conv, err = scanConversation(tx.QueryRow(ctx, `
+		INSERT INTO conversations (is_group, created_by) VALUES (FALSE, $1)
+		RETURNING `+conversationColumns, userID))
+	if err != nil {
+		return nil, false, translateUserError(err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO conversation_participants (conversation_id, user_id, role)
+		VALUES ($1, $2, 'member'), ($1, $3, 'member')
+	`, conv.ID, userID, otherUserID)
+	if err != nil {
+		return nil, false, translateUserError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, err
+	}
+	return conv, true, nil
+}
+
+// CreateGroupConversation creates a group owned by creatorID, who becomes its first admin
+func CreateGroupConversation(db *pgxpool.Pool, creatorID, title string, participantIDs []string) (*Conversation, error) {
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	conv, err := scanConversation(tx.QueryRow(ctx, `
+		INSERT INTO conversations (is_group, title, created_by) VALUES (TRUE, $1, $2)
+		RETURNING `+conversationColumns, title, creatorID))
+	if err != nil {
+		return nil, translateUserError(err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO conversation_participants (conversation_id, user_id, role)
+		VALUES ($1, $2, 'admin')
+	`, conv.ID, creatorID)
+	if err != nil {
+		return nil, translateUserError(err)
+	}
+
+	if err := addParticipants(ctx, tx, conv.ID, participantIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return conv, nil
+}
+
+// ListConversations returns every conversation a user belongs to, most
+// recently active first. Unread counts leave out messages deleted for everyone
+// or hidden by the user.
+func ListConversations(db *pgxpool.Pool, userID string) ([]ConversationSummary, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT c.id, COALESCE(c.is_group, FALSE), c.title, c.created_by, c.moderation_policy, c.created_at,
+		       ARRAY(SELECT user_id::text FROM conversation_participants WHERE conversation_id = c.id ORDER BY joined_at),
+		       lm.id, lm.sender_id, lm.content, lm.reply_to_message_id, lm.created_at, lm.edited_at, lm.deleted_at,
+		       (SELECT COUNT(*) FROM messages m
+		        WHERE m.conversation_id = c.id
+		          AND m.id > COALESCE(p.last_read_message_id, 0)
+		          AND m.sender_id <> p.user_id
+		          AND m.deleted_at IS NULL
+		          AND `+notHiddenFor("p.user_id")+`)
+		FROM conversation_participants p
+		JOIN conversations c ON c.id = p.conversation_id
+		LEFT JOIN LATERAL (
+			SELECT m.id, m.sender_id, m.content, m.reply_to_message_id, m.created_at, m.edited_at, m.deleted_at
+			FROM messages m
+			WHERE m.conversation_id = c.id
+			  AND `+notHiddenFor("p.user_id")+`
+			ORDER BY m.id DESC LIMIT 1
+		) lm ON TRUE
+		WHERE p.user_id = $1
+		ORDER BY COALESCE(lm.created_at, c.created_at) DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := []ConversationSummary{}
+	for rows.Next() {
+		var (
+			summary       ConversationSummary
+			lastID        *int
+			lastSenderID  *string
+			lastContent   *string
+			lastReplyTo   *int
+			lastCreatedAt *time.Time
+			lastEditedAt  *time.Time
+			lastDeletedAt *time.Time
+		)
+		err := rows.Scan(
+			&summary.ID, &summary.IsGroup, &summary.Title, &summary.CreatedBy, &summary.ModerationPolicy, &summary.CreatedAt,
+			&summary.Participants,
+			&lastID, &lastSenderID, &lastContent, &lastReplyTo, &lastCreatedAt, &lastEditedAt, &lastDeletedAt,
+			&summary.UnreadCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if lastID != nil {
+			summary.LastMessage = &Message{
+				ID:               *lastID,
+				ConversationID:   summary.ID,
+				SenderID:         *lastSenderID,
+				Content:          *lastContent,
+				ReplyToMessageID: lastReplyTo,
+				CreatedAt:        *lastCreatedAt,
+				EditedAt:         lastEditedAt,
+				DeletedAt:        lastDeletedAt,
+			}
+		}
+
+		conversations = append(conversations, summary)
+	}
+	return conversations, rows.Err()
+}
+
+// RenameConversation changes a group's title
+func RenameConversation(db *pgxpool.Pool, conversationID int, title string) error {
+	tag, err := db.Exec(context.Background(), `UPDATE conversations SET title = $1 WHERE id = $2`, title, conversationID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrConversationNotFound
+	}
+	return nil
+}
+
+// SetModerationPolicy changes how a conversation treats messages the word filter objects to
+func SetModerationPolicy(db *pgxpool.Pool, conversationID int, policy string) error {
+	tag, err := db.Exec(context.Background(), `UPDATE conversations SET moderation_policy = $1 WHERE id = $2`, policy, conversationID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrConversationNotFound
+	}
+	return nil
+}
+
+// SetDirectModerationPolicy records a participant's choice of policy for a
+// direct conversation. The stricter policies, reject and mask, apply at once
+// and withdraw any agreement to flag. Flag lets messages through unmasked to
+// both sides, so it only applies once every participant has asked for it. It
+// reports whether the conversation's policy changed.
+func SetDirectModerationPolicy(db *pgxpool.Pool, conversationID int, userID, policy string) (applied bool, err error) {
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the conversation so two participants agreeing at once both see each other's vote
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM conversations WHERE id = $1 FOR UPDATE`, conversationID); err != nil {
+		return false, err
+	}
+
+	if policy != PolicyFlag {
+		if _, err := tx.Exec(ctx, `
+			UPDATE conversation_participants SET agrees_to_flag = FALSE WHERE conversation_id = $1
+		`, conversationID); err != nil {
+			return false, err
+		}
+		tag, err := tx.Exec(ctx, `UPDATE conversations SET moderation_policy = $1 WHERE id = $2`, policy, conversationID)
+		if err != nil {
+			return false, err
+		}
+		if tag.RowsAffected() == 0 {
+			return false, ErrConversationNotFound
+		}
+		return true, tx.Commit(ctx)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE conversation_participants SET agrees_to_flag = TRUE WHERE conversation_id = $1 AND user_id = $2
+	`, conversationID, userID)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, ErrNotParticipant
+	}
+
+	tag, err = tx.Exec(ctx, `
+		UPDATE conversations SET moderation_policy = $2
+		WHERE id = $1
+		  AND NOT EXISTS(SELECT 1 FROM conversation_participants WHERE conversation_id = $1 AND NOT agrees_to_flag)
+	`, conversationID, PolicyFlag)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, tx.Commit(ctx)
+}
+
+// GetConversationParticipants returns the user IDs of everyone in a conversation
+func GetConversationParticipants(db *pgxpool.Pool, conversationID int) ([]string, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT user_id FROM conversation_participants WHERE conversation_id = $1
+	`, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := []string{}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		participants = append(participants, userID)
+	}
+	return participants, rows.Err()
+}
+
+// ListParticipants returns the members of a conversation with their roles
+func ListParticipants(db *pgxpool.Pool, conversationID int) ([]Participant, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT user_id, role, joined_at FROM conversation_participants
+		WHERE conversation_id = $1 ORDER BY joined_at
+	`, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := []Participant{}
+	for rows.Next() {
+		var p Participant
+		if err := rows.Scan(&p.UserID, &p.Role, &p.JoinedAt); err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+	return participants, rows.Err()
+}
+
+// IsParticipant reports whether a user belongs to a conversation
+func IsParticipant(db *pgxpool.Pool, conversationID int, userID string) (bool, error) {
+	var exists bool
+	err := db.QueryRow(context.Background(), `
+		SELECT EXISTS(
+			SELECT 1 FROM conversation_participants
+			WHERE conversation_id = $1 AND user_id = $2
+		)
+	`, conversationID, userID).Scan(&exists)
+	return exists, err
+}
+
+// GetParticipantRole returns a user's role in a conversation, or ErrNotParticipant
+func GetParticipantRole(db *pgxpool.Pool, conversationID int, userID string) (string, error) {
+	var role string
+	err := db.QueryRow(context.Background(), `
+		SELECT role FROM conversation_participants WHERE conversation_id = $1 AND user_id = $2
+	`, conversationID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotParticipant
+	}
+	return role, err
+}
+
+// AddParticipants adds members to a conversation, ignoring users who are already in it
+func AddParticipants(db *pgxpool.Pool, conversationID int, userIDs []string) error {
+	return addParticipants(context.Background(), db, conversationID, userIDs)
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// addParticipants inserts new members with their delivery and read markers at the
+// newest message, so joining a group doesn't flood them with its history
+func addParticipants(ctx context.Context, db execer, conversationID int, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := db.Exec(ctx, `
+		INSERT INTO conversation_participants
+			(conversation_id, user_id, role, last_delivered_message_id, last_read_message_id)
+		SELECT $1, user_id, 'member', latest.id, latest.id
+		FROM UNNEST($2::uuid[]) AS user_id,
+		     (SELECT MAX(id) AS id FROM messages WHERE conversation_id = $1) AS latest
+		ON CONFLICT (conversation_id, user_id) DO NOTHING
+	`, conversationID, userIDs)
+	return translateUserError(err)
+}
+
+// SetParticipantRole promotes or demotes a member of a conversation
+func SetParticipantRole(db *pgxpool.Pool, conversationID int, userID, role string) error {
+	tag, err := db.Exec(context.Background(), `
+		UPDATE conversation_participants SET role = $1 WHERE conversation_id = $2 AND user_id = $3
+	`, role, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotParticipant
+	}
+	return nil
+}
+
+// RemoveParticipant removes a member from a conversation. If that leaves a
+// group without an admin, the longest-standing remaining member is promoted.
+func RemoveParticipant(db *pgxpool.Pool, conversationID int, userID string) error {
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		DELETE FROM conversation_participants WHERE conversation_id = $1 AND user_id = $2
+	`, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotParticipant
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE conversation_participants SET role = 'admin'
+		WHERE conversation_id = $1
+		  AND NOT EXISTS(SELECT 1 FROM conversation_participants WHERE conversation_id = $1 AND role = 'admin')
+		  AND user_id = (SELECT user_id FROM conversation_participants WHERE conversation_id = $1 ORDER BY joined_at LIMIT 1)
+	`, conversationID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// MarkConversationDelivered moves a participant's delivery marker forward to
+// messageID, capped at the conversation's newest message. It returns the new
+// marker, or 0 if it did not move, so callers only announce real progress.
+func MarkConversationDelivered(db *pgxpool.Pool, conversationID int, userID string, messageID int) (int, error) {
+	var marker int
+	err := db.QueryRow(context.Background(), `
+		UPDATE conversation_participants
+		SET last_delivered_message_id = LEAST($3, (SELECT MAX(id) FROM messages WHERE conversation_id = $1))
+		WHERE conversation_id = $1 AND user_id = $2
+		  AND COALESCE(last_delivered_message_id, 0) < LEAST($3, (SELECT MAX(id) FROM messages WHERE conversation_id = $1))
+		RETURNING last_delivered_message_id
+	`, conversationID, userID, messageID).Scan(&marker)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return marker, err
+}
+
+// MarkConversationRead moves a participant's read marker forward to messageID,
+// capped at the conversation's newest message. Anything read has also been
+// delivered, so the delivery marker is moved along with it. It returns the new
+// read marker, or 0 if it did not move.
+func MarkConversationRead(db *pgxpool.Pool, conversationID int, userID string, messageID int) (int, error) {
+	var marker int
+	err := db.QueryRow(context.Background(), `
+		UPDATE conversation_participants
+		SET last_read_message_id = LEAST($3, (SELECT MAX(id) FROM messages WHERE conversation_id = $1)),
+		    last_delivered_message_id = GREATEST(
+		        COALESCE(last_delivered_message_id, 0),
+		        LEAST($3, (SELECT MAX(id) FROM messages WHERE conversation_id = $1)))
+		WHERE conversation_id = $1 AND user_id = $2
+		  AND COALESCE(last_read_message_id, 0) < LEAST($3, (SELECT MAX(id) FROM messages WHERE conversation_id = $1))
+		RETURNING last_read_message_id
+	`, conversationID, userID, messageID).Scan(&marker)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return marker, err
+}
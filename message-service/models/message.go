@@ -0,0 +1,374 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Pagination bounds for conversation history
+const (
+	DefaultMessageLimit = 50
+	MaxMessageLimit     = 100
+)
+
+// Maximum number of messages returned for a reply thread
+const MaxThreadLength = 500
+
+var (
+	// ErrInvalidReply is returned when a reply points at a message from another conversation
+	ErrInvalidReply = errors.New("reply_to_message_id does not belong to this conversation")
+
+	ErrMessageNotFound   = errors.New("message not found")
+	ErrNotSender         = errors.New("only the sender can change this message")
+	ErrMessageDeleted    = errors.New("message has been deleted")
+	ErrEditWindowExpired = errors.New("message can no longer be edited")
+)
+
+// Message Model
+type Message struct {
+	ID               int        `json:"id"`
+	ConversationID   int        `json:"conversation_id"`
+	SenderID         string     `json:"sender_id"`
+	Content          string     `json:"content"`
+	ReplyToMessageID *int       `json:"reply_to_message_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	EditedAt         *time.Time `json:"edited_at"`
+	DeletedAt        *time.Time `json:"deleted_at"`
+}
+
+// MessageEdit is a previous version of an edited message
+type MessageEdit struct {
+	PreviousContent string    `json:"previous_content"`
+	EditedAt        time.Time `json:"edited_at"`
+}
+
+const messageColumns = `id, conversation_id, sender_id, content, reply_to_message_id, created_at, edited_at, deleted_at`
+
+// notHiddenFor filters out messages the user in the given parameter deleted for themselves
+func notHiddenFor(param string) string {
+	return `NOT EXISTS(SELECT 1 FROM hidden_messages h WHERE h.message_id = m.id AND h.user_id = ` + param + `)`
+}
+
+func scanMessage(row pgx.Row) (*Message, error) {
+	var msg Message
+	err := row.Scan(
+		&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.Content,
+		&msg.ReplyToMessageID, &msg.CreatedAt, &msg.EditedAt, &msg.DeletedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func scanMessages(rows pgx.Rows) ([]Message, error) {
+	defer rows.Close()
+
+	messages := []Message{}
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *msg)
+	}
+	return messages, rows.Err()
+}
+
+// CreateMessage stores a new message and returns it with its generated ID and
+// timestamp. A non-empty clientMessageID that the sender already used returns
+// the originally stored message with created=false instead of a duplicate.
+// flagged marks a message the word filter objected to for later review.
+func CreateMessage(db *pgxpool.Pool, conversationID int, senderID, content string, replyToMessageID *int, clientMessageID string, flagged bool) (msg *Message, created bool, err error) {
+	if replyToMessageID != nil {
+		var sameConversation bool
+		err := db.QueryRow(context.Background(), `
+			SELECT EXISTS(SELECT 1 FROM messages WHERE id = $1 AND conversation_id = $2)
+		`, *replyToMessageID, conversationID).Scan(&sameConversation)
+		if err != nil {
+			return nil, false, err
+		}
+		if !sameConversation {
+			return nil, false, ErrInvalidReply
+		}
+	}
+
+	var clientID *string
+	if clientMessageID != "" {
+		clientID = &clientMessageID
+	}
+
+	query := `INSERT INTO messages (conversation_id, sender_id, content, reply_to_message_id, client_message_id, flagged)
+              VALUES ($1, $2, $3, $4, $5, $6)
+              ON CONFLICT (sender_id, client_message_id) DO NOTHING
+              RETURNING ` + messageColumns
+	msg, err = scanMessage(db.QueryRow(context.Background(), query, conversationID, senderID, content, replyToMessageID, clientID, flagged))
+	if err == nil {
+		return msg, true, nil
+	}
+	if !errors.Is(err, ErrMessageNotFound) {
+		return nil, false, err
+	}
+
+	// A retry of a message we already stored
+	msg, err = scanMessage(db.QueryRow(context.Background(),
+		`SELECT `+messageColumns+` FROM messages WHERE sender_id = $1 AND client_message_id = $2`,
+		senderID, clientMessageID))
+	if err != nil {
+		return nil, false, err
+	}
+	return msg, false, nil
+}
+
+// GetMessages returns a page of a conversation's history in ascending ID order,
+// leaving out messages userID deleted for themselves.
+// With beforeID set it returns the newest messages older than that ID, with afterID
+// set the oldest messages newer than it, and with neither the latest messages.
+// hasMore reports whether further messages exist in the direction of travel.
+func GetMessages(db *pgxpool.Pool, conversationID int, userID string, beforeID, afterID, limit int) (messages []Message, hasMore bool, err error) {
+	if limit <= 0 {
+		limit = DefaultMessageLimit
+	}
+	limit = min(limit, MaxMessageLimit)
+
+	var rows pgx.Rows
+	ascending := afterID > 0
+	switch {
+	case ascending:
+		rows, err = db.Query(context.Background(), `
+			SELECT `+messageColumns+` FROM messages m
+			WHERE conversation_id = $1 AND id > $2 AND `+notHiddenFor("$4")+`
+			ORDER BY id ASC LIMIT $3
+		`, conversationID, afterID, limit+1, userID)
+	case beforeID > 0:
+		rows, err = db.Query(context.Background(), `
+			SELECT `+messageColumns+` FROM messages m
+			WHERE conversation_id = $1 AND id < $2 AND `+notHiddenFor("$4")+`
+			ORDER BY id DESC LIMIT $3
+		`, conversationID, beforeID, limit+1, userID)
+	default:
+		rows, err = db.Query(context.Background(), `
+			SELECT `+messageColumns+` FROM messages m
+			WHERE conversation_id = $1 AND `+notHiddenFor("$3")+`
+			ORDER BY id DESC LIMIT $2
+		`, conversationID, limit+1, userID)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	messages, err = scanMessages(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The extra row only tells us whether another page exists
+	if len(messages) > limit {
+		hasMore = true
+		messages = messages[:limit]
+	}
+
+	if !ascending {
+		slices.Reverse(messages)
+	}
+
+	return messages, hasMore, nil
+}
+
+// GetUndeliveredMessages returns messages from other participants that have not
+// reached any of the user's devices yet, across all their conversations, in ID
+// order starting after afterID. Messages deleted before they arrived, or hidden
+// by the user, are left out.
+func GetUndeliveredMessages(db *pgxpool.Pool, userID string, afterID, limit int) ([]Message, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT m.id, m.conversation_id, m.sender_id, m.content, m.reply_to_message_id, m.created_at, m.edited_at, m.deleted_at
+		FROM messages m
+		JOIN conversation_participants p ON p.conversation_id = m.conversation_id AND p.user_id = $1
+		WHERE m.id > GREATEST(COALESCE(p.last_delivered_message_id, 0), $2)
+		  AND m.sender_id <> $1
+		  AND m.deleted_at IS NULL
+		  AND `+notHiddenFor("$1")+`
+		ORDER BY m.id ASC
+		LIMIT $3
+	`, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
+
+// GetMessage retrieves a message by ID
+func GetMessage(db *pgxpool.Pool, messageID int) (*Message, error) {
+	return scanMessage(db.QueryRow(context.Background(),
+		`SELECT `+messageColumns+` FROM messages WHERE id = $1`, messageID))
+}
+
+// EditMessage replaces a message's content, keeping the old version in its edit
+// history. Only the sender may edit, and only within window of sending it.
+// flagged marks the message for review; an earlier flag is never cleared.
+func EditMessage(db *pgxpool.Pool, messageID int, editorID, content string, window time.Duration, flagged bool) (*Message, error) {
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	msg, err := scanMessage(tx.QueryRow(ctx,
+		`SELECT `+messageColumns+` FROM messages WHERE id = $1 FOR UPDATE`, messageID))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case msg.SenderID != editorID:
+		return nil, ErrNotSender
+	case msg.DeletedAt != nil:
+		return nil, ErrMessageDeleted
+	}
+	previous := msg.Content
+
+	// The window is checked by the database against its own clock, since
+	// created_at is stored without a time zone in the session's zone
+	edited, err := scanMessage(tx.QueryRow(ctx, `
+		UPDATE messages SET content = $1, edited_at = CURRENT_TIMESTAMP, flagged = flagged OR $3
+		WHERE id = $2 AND created_at > CURRENT_TIMESTAMP - make_interval(secs => $4)
+		RETURNING `+messageColumns, content, messageID, flagged, window.Seconds()))
+	if errors.Is(err, ErrMessageNotFound) {
+		return nil, ErrEditWindowExpired
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO message_edits (message_id, previous_content) VALUES ($1, $2)
+	`, messageID, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return edited, nil
+}
+
+// GetMessageEdits returns a message's previous versions, oldest first
+func GetMessageEdits(db *pgxpool.Pool, messageID int) ([]MessageEdit, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT previous_content, edited_at FROM message_edits
+		WHERE message_id = $1 ORDER BY id
+	`, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edits := []MessageEdit{}
+	for rows.Next() {
+		var edit MessageEdit
+		if err := rows.Scan(&edit.PreviousContent, &edit.EditedAt); err != nil {
+			return nil, err
+		}
+		edits = append(edits, edit)
+	}
+	return edits, rows.Err()
+}
+
+// DeleteMessageForEveryone turns a message into a tombstone: its content and
+// edit history are erased but the row stays so replies and pagination still line up
+func DeleteMessageForEveryone(db *pgxpool.Pool, messageID int, userID string) (*Message, error) {
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	msg, err := scanMessage(tx.QueryRow(ctx,
+		`SELECT `+messageColumns+` FROM messages WHERE id = $1 FOR UPDATE`, messageID))
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.SenderID != userID {
+		return nil, ErrNotSender
+	}
+	if msg.DeletedAt != nil {
+		return msg, nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM message_edits WHERE message_id = $1`, messageID); err != nil {
+		return nil, err
+	}
+
+	msg, err = scanMessage(tx.QueryRow(ctx, `
+		UPDATE messages SET content = '', deleted_at = CURRENT_TIMESTAMP
+		WHERE id = $1 RETURNING `+messageColumns, messageID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// HideMessage deletes a message from one user's view only
+func HideMessage(db *pgxpool.Pool, messageID int, userID string) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO hidden_messages (message_id, user_id) VALUES ($1, $2)
+		ON CONFLICT (message_id, user_id) DO NOTHING
+	`, messageID, userID)
+	return err
+}
+
+// GetThread returns a message followed by every reply beneath it, however deeply
+// nested, in ID order, leaving out messages userID deleted for themselves
+func GetThread(db *pgxpool.Pool, rootMessageID int, userID string) ([]Message, error) {
+	rows, err := db.Query(context.Background(), `
+		WITH RECURSIVE thread AS (
+			SELECT id FROM messages WHERE id = $1
+			UNION
+			SELECT r.id FROM messages r JOIN thread t ON r.reply_to_message_id = t.id
+		)
+		SELECT `+messageColumns+` FROM messages m
+		WHERE id IN (SELECT id FROM thread) AND `+notHiddenFor("$2")+`
+		ORDER BY id
+		LIMIT $3
+	`, rootMessageID, userID, MaxThreadLength)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
+
+// GetFlaggedMessages returns a conversation's messages awaiting review, newest first
+func GetFlaggedMessages(db *pgxpool.Pool, conversationID, limit int) ([]Message, error) {
+	if limit <= 0 {
+		limit = DefaultMessageLimit
+	}
+	limit = min(limit, MaxMessageLimit)
+
+	rows, err := db.Query(context.Background(), `
+		SELECT `+messageColumns+` FROM messages
+		WHERE conversation_id = $1 AND flagged AND deleted_at IS NULL
+		ORDER BY id DESC LIMIT $2
+	`, conversationID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Connection statuses from identity_service's user_connections table
+const (
+	ConnectionNone     = "none"
+	ConnectionPending  = "pending"
+	ConnectionAccepted = "accepted"
+	ConnectionBlocked  = "blocked"
+)
+
+var (
+	ErrUsersBlocked = errors.New("one of the users has blocked the other")
+	ErrNotConnected = errors.New("users are not connected")
+)
+
+// GetConnectionStatus returns the relationship between two users. A block in
+// either direction wins over any other row, so blocked users never look connected.
+func GetConnectionStatus(db *pgxpool.Pool, userID, otherUserID string) (string, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT status FROM user_connections
+		WHERE (requester_id = $1 AND addressee_id = $2)
+		   OR (requester_id = $2 AND addressee_id = $1)
+	`, userID, otherUserID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	status := ConnectionNone
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return "", err
+		}
+		switch {
+		case s == ConnectionBlocked:
+			status = ConnectionBlocked
+		case s == ConnectionAccepted && status != ConnectionBlocked:
+			status = ConnectionAccepted
+		case status == ConnectionNone:
+			status = s
+		}
+	}
+	return status, rows.Err()
+}
+
+// CanMessageDirectly returns nil only when two users have an accepted connection
+// and neither has blocked the other
+func CanMessageDirectly(db *pgxpool.Pool, userID, otherUserID string) error {
+	status, err := GetConnectionStatus(db, userID, otherUserID)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case ConnectionAccepted:
+		return nil
+	case ConnectionBlocked:
+		return ErrUsersBlocked
+	default:
+		return ErrNotConnected
+	}
+}
+
+// ListAcceptedConnections returns the IDs of users with an accepted connection to
+// userID, leaving out anyone on either side of a block
+func ListAcceptedConnections(db *pgxpool.Pool, userID string) ([]string, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT CASE WHEN requester_id = $1 THEN addressee_id ELSE requester_id END AS other_id
+		FROM user_connections uc
+		WHERE (requester_id = $1 OR addressee_id = $1) AND status = 'accepted'
+		  AND NOT EXISTS(
+			SELECT 1 FROM user_connections b
+			WHERE b.status = 'blocked'
+			  AND ((b.requester_id = uc.requester_id AND b.addressee_id = uc.addressee_id)
+			    OR (b.requester_id = uc.addressee_id AND b.addressee_id = uc.requester_id))
+		  )
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	connections := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		connections = append(connections, id)
+	}
+	return connections, rows.Err()
+}
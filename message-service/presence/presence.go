@@ -0,0 +1,325 @@
+// Package presence tracks which users are online across every message-service replica
+package presence
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joy095/message-service/hub"
+	"github.com/joy095/message-service/logger"
+	"github.com/joy095/message-service/protocol"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Presence statuses
+const (
+	StatusOnline  = "online"
+	StatusOffline = "offline"
+)
+
+const (
+	// A connection counts as online for this long after its last heartbeat, so
+	// users on a crashed replica drop offline on their own
+	connectionTTL = 90 * time.Second
+
+	// How often live connections on this replica renew their entries
+	heartbeatPeriod = 30 * time.Second
+
+	keyPrefix = "message-service:presence:"
+)
+
+// connectionsKey holds a sorted set of a user's connections scored by expiry time
+func connectionsKey(userID string) string {
+	return keyPrefix + "connections:" + userID
+}
+
+// expiriesKey holds a sorted set of every connection on every replica, as
+// "<user ID>:<member ID>" scored by expiry time, so the connections of a
+// crashed replica can be found and their users reported offline
+const expiriesKey = keyPrefix + "expiries"
+
+func expiryMember(userID, member string) string {
+	return userID + ":" + member
+}
+
+func lastSeenKey(userID string) string {
+	return keyPrefix + "last-seen:" + userID
+}
+
+// Tracker records connections in Redis with a TTL. Without Redis it falls back
+// to the connections of this process.
+type Tracker struct {
+	rdb *redis.Client
+	hub *hub.Hub
+
+	// local maps each connection on this replica to its member ID in Redis
+	local map[*hub.Client]string
+	// lastSeen is only used without Redis
+	lastSeen map[string]time.Time
+	mu       sync.Mutex
+
+	// now is the clock expiry times are computed with
+	now func() time.Time
+}
+
+// NewTracker creates a Tracker. rdb may be nil when running as a single replica.
+func NewTracker(rdb *redis.Client, h *hub.Hub) *Tracker {
+	return &Tracker{
+		rdb:      rdb,
+		hub:      h,
+		local:    make(map[*hub.Client]string),
+		lastSeen: make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Run renews the entries of this replica's connections until the process
+// exits. It also sweeps connections whose replica stopped renewing them, and
+// passes the presence of each user that left offline to wentOffline.
+func (t *Tracker) Run(wentOffline func(protocol.Presence)) {
+	if t.rdb == nil {
+		return
+	}
+
+	ticker := time.NewTicker(heartbeatPeriod)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := t.renew(t.snapshot()); err != nil {
+			logger.ErrorLogger.Errorf("Failed to renew presence: %v", err)
+		}
+		if err := t.sweep(wentOffline); err != nil {
+			logger.ErrorLogger.Errorf("Failed to sweep expired presence: %v", err)
+		}
+	}
+}
+
+// snapshot returns the member ID of each connection on this replica, mapped to its user
+func (t *Tracker) snapshot() map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entries := make(map[string]string, len(t.local))
+	for client, member := range t.local {
+		entries[member] = client.UserID
+	}
+	return entries
+}
+
+// renew pushes back the expiry of the given connections. Only entries still in
+// Redis are renewed, so a connection that disconnected after the snapshot was
+// taken isn't added back.
+func (t *Tracker) renew(entries map[string]string) error {
+	expiry := float64(t.now().Add(connectionTTL).Unix())
+	_, err := t.rdb.Pipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		for member, userID := range entries {
+			pipe.ZAddXX(context.Background(), connectionsKey(userID), redis.Z{Score: expiry, Member: member})
+			pipe.Expire(context.Background(), connectionsKey(userID), connectionTTL)
+			pipe.ZAddXX(context.Background(), expiriesKey, redis.Z{Score: expiry, Member: expiryMember(userID, member)})
+		}
+		return nil
+	})
+	return err
+}
+
+// sweep removes connections that expired without disconnecting, which happens
+// when their replica crashes, and reports the users that left offline. Each
+// replica sweeps, but only the one that removes a connection reports it.
+func (t *Tracker) sweep(wentOffline func(protocol.Presence)) error {
+	now := t.now()
+	expired, err := t.rdb.ZRangeByScoreWithScores(context.Background(), expiriesKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, z := range expired {
+		entry := z.Member.(string)
+		removed, err := t.rdb.ZRem(context.Background(), expiriesKey, entry).Result()
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			continue
+		}
+
+		userID, member, _ := strings.Cut(entry, ":")
+		key := connectionsKey(userID)
+
+		var count *redis.IntCmd
+		_, err = t.rdb.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+			pipe.ZRem(context.Background(), key, member)
+			pipe.ZRemRangeByScore(context.Background(), key, "-inf", strconv.FormatInt(now.Unix(), 10))
+			count = pipe.ZCard(context.Background(), key)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if count.Val() > 0 {
+			continue
+		}
+
+		// The user was last seen at the connection's final heartbeat
+		lastSeen, err := t.recordLastSeen(userID, time.Unix(int64(z.Score), 0).Add(-connectionTTL))
+		if err != nil {
+			return err
+		}
+		if wentOffline != nil {
+			wentOffline(protocol.Presence{UserID: userID, Status: StatusOffline, LastSeen: &lastSeen})
+		}
+	}
+	return nil
+}
+
+// recordLastSeen stores when a user was last seen, unless a later time is
+// already stored, and returns the time stored
+func (t *Tracker) recordLastSeen(userID string, seen time.Time) (time.Time, error) {
+	stored, err := t.rdb.Get(context.Background(), lastSeenKey(userID)).Int64()
+	if err == nil && stored >= seen.Unix() {
+		return time.Unix(stored, 0), nil
+	}
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return time.Time{}, err
+	}
+	return seen, t.rdb.Set(context.Background(), lastSeenKey(userID), seen.Unix(), 0).Err()
+}
+
+// Connect records a new connection and reports whether the user just came online
+func (t *Tracker) Connect(client *hub.Client) (cameOnline bool, err error) {
+	member := newMemberID()
+
+	t.mu.Lock()
+	t.local[client] = member
+	t.mu.Unlock()
+
+	if t.rdb == nil {
+		// Only this connection counts if the user just came online
+		return t.localConnections(client.UserID) == 1, nil
+	}
+
+	key := connectionsKey(client.UserID)
+	now := t.now()
+	expiry := float64(now.Add(connectionTTL).Unix())
+
+	var count *redis.IntCmd
+	_, err = t.rdb.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(context.Background(), key, "-inf", strconv.FormatInt(now.Unix(), 10))
+		pipe.ZAdd(context.Background(), key, redis.Z{Score: expiry, Member: member})
+		pipe.Expire(context.Background(), key, connectionTTL)
+		pipe.ZAdd(context.Background(), expiriesKey, redis.Z{Score: expiry, Member: expiryMember(client.UserID, member)})
+		count = pipe.ZCard(context.Background(), key)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return count.Val() == 1, nil
+}
+
+// Disconnect removes a connection and reports whether that took the user offline
+func (t *Tracker) Disconnect(client *hub.Client) (wentOffline bool, lastSeen time.Time, err error) {
+	t.mu.Lock()
+	member, ok := t.local[client]
+	delete(t.local, client)
+	t.mu.Unlock()
+
+	if !ok {
+		return false, time.Time{}, nil
+	}
+
+	now := t.now()
+
+	if t.rdb == nil {
+		if t.localConnections(client.UserID) > 0 {
+			return false, time.Time{}, nil
+		}
+		t.mu.Lock()
+		t.lastSeen[client.UserID] = now
+		t.mu.Unlock()
+		return true, now, nil
+	}
+
+	key := connectionsKey(client.UserID)
+
+	var removed, count *redis.IntCmd
+	_, err = t.rdb.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		removed = pipe.ZRem(context.Background(), expiriesKey, expiryMember(client.UserID, member))
+		pipe.ZRem(context.Background(), key, member)
+		pipe.ZRemRangeByScore(context.Background(), key, "-inf", strconv.FormatInt(now.Unix(), 10))
+		count = pipe.ZCard(context.Background(), key)
+		pipe.Set(context.Background(), lastSeenKey(client.UserID), now.Unix(), 0)
+		return nil
+	})
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	// If a sweep already removed the connection, it reported the user offline
+	return count.Val() == 0 && removed.Val() == 1, now, nil
+}
+
+// Get returns a user's current presence
+func (t *Tracker) Get(userID string) (protocol.Presence, error) {
+	if t.rdb == nil {
+		if t.hub.IsOnline(userID) {
+			return protocol.Presence{UserID: userID, Status: StatusOnline}, nil
+		}
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		p := protocol.Presence{UserID: userID, Status: StatusOffline}
+		if seen, ok := t.lastSeen[userID]; ok {
+			p.LastSeen = &seen
+		}
+		return p, nil
+	}
+
+	now := strconv.FormatInt(t.now().Unix(), 10)
+	online, err := t.rdb.ZCount(context.Background(), connectionsKey(userID), now, "+inf").Result()
+	if err != nil {
+		return protocol.Presence{}, err
+	}
+	if online > 0 {
+		return protocol.Presence{UserID: userID, Status: StatusOnline}, nil
+	}
+
+	p := protocol.Presence{UserID: userID, Status: StatusOffline}
+	seen, err := t.rdb.Get(context.Background(), lastSeenKey(userID)).Int64()
+	if err == nil {
+		lastSeen := time.Unix(seen, 0)
+		p.LastSeen = &lastSeen
+	} else if !errors.Is(err, redis.Nil) {
+		return protocol.Presence{}, err
+	}
+	return p, nil
+}
+
+func (t *Tracker) localConnections(userID string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := 0
+	for client := range t.local {
+		if client.UserID == userID {
+			count++
+		}
+	}
+	return count
+}
+
+// newMemberID identifies one connection in a user's sorted set
+func newMemberID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
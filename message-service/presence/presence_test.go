@@ -0,0 +1,193 @@
+package presence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joy095/message-service/hub"
+	"github.com/joy095/message-service/protocol"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// clock is a fake time shared by the trackers of a test
+type clock struct{ now time.Time }
+
+func (c *clock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// newReplica returns the tracker of one replica, keeping presence in mr
+func newReplica(t *testing.T, mr *miniredis.Miniredis, c *clock) *Tracker {
+	t.Helper()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	tracker := NewTracker(rdb, nil)
+	tracker.now = func() time.Time { return c.now }
+	return tracker
+}
+
+func connect(t *testing.T, tracker *Tracker, userID string) *hub.Client {
+	t.Helper()
+	client := &hub.Client{UserID: userID}
+	if _, err := tracker.Connect(client); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	return client
+}
+
+func status(t *testing.T, tracker *Tracker, userID string) string {
+	t.Helper()
+	p, err := tracker.Get(userID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	return p.Status
+}
+
+// sweep runs a sweep and returns the presence changes it reported
+func sweep(t *testing.T, tracker *Tracker) []protocol.Presence {
+	t.Helper()
+	var reported []protocol.Presence
+	if err := tracker.sweep(func(p protocol.Presence) { reported = append(reported, p) }); err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	return reported
+}
+
+func TestRenewKeepsConnectionsOnline(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := &clock{now: time.Unix(1_700_000_000, 0)}
+	tracker := newReplica(t, mr, c)
+
+	connect(t, tracker, "alice")
+	for range 5 {
+		c.advance(heartbeatPeriod)
+		if err := tracker.renew(tracker.snapshot()); err != nil {
+			t.Fatalf("renew: %v", err)
+		}
+	}
+
+	if got := status(t, tracker, "alice"); got != StatusOnline {
+		t.Errorf("alice is %s after heartbeats outlasting the TTL, want online", got)
+	}
+	if reported := sweep(t, tracker); len(reported) != 0 {
+		t.Errorf("sweep reported %+v for a live connection", reported)
+	}
+}
+
+func TestRenewDoesNotRestoreDisconnected(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := &clock{now: time.Unix(1_700_000_000, 0)}
+	tracker := newReplica(t, mr, c)
+
+	client := connect(t, tracker, "alice")
+
+	// The heartbeat takes its snapshot, then the client disconnects before the renewal
+	entries := tracker.snapshot()
+	wentOffline, _, err := tracker.Disconnect(client)
+	if err != nil || !wentOffline {
+		t.Fatalf("Disconnect = %v, %v; want the user offline", wentOffline, err)
+	}
+	if err := tracker.renew(entries); err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+
+	if got := status(t, tracker, "alice"); got != StatusOffline {
+		t.Errorf("alice is %s after disconnecting, want offline", got)
+	}
+	if mr.Exists(connectionsKey("alice")) {
+		t.Errorf("the heartbeat added the disconnected connection back: %v", mr.Keys())
+	}
+	if members, _ := mr.ZMembers(expiriesKey); len(members) != 0 {
+		t.Errorf("expiries still hold %v", members)
+	}
+}
+
+func TestSweepReportsCrashedReplica(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := &clock{now: time.Unix(1_700_000_000, 0)}
+	crashed := newReplica(t, mr, c)
+	survivor := newReplica(t, mr, c)
+	connectedAt := c.now
+
+	connect(t, crashed, "alice")
+	// Bob is connected to both replicas, so he stays online
+	connect(t, crashed, "bob")
+	connect(t, survivor, "bob")
+
+	// Only the surviving replica keeps renewing
+	for range 4 {
+		c.advance(heartbeatPeriod)
+		if err := survivor.renew(survivor.snapshot()); err != nil {
+			t.Fatalf("renew: %v", err)
+		}
+	}
+
+	reported := sweep(t, survivor)
+	if len(reported) != 1 {
+		t.Fatalf("sweep reported %+v, want alice going offline", reported)
+	}
+	p := reported[0]
+	if p.UserID != "alice" || p.Status != StatusOffline || p.LastSeen == nil || !p.LastSeen.Equal(connectedAt) {
+		t.Errorf("sweep reported %+v, want alice offline, last seen at %v", p, connectedAt)
+	}
+
+	if got := status(t, survivor, "alice"); got != StatusOffline {
+		t.Errorf("alice is %s, want offline", got)
+	}
+	if got := status(t, survivor, "bob"); got != StatusOnline {
+		t.Errorf("bob is %s, want online", got)
+	}
+
+	// Another replica sweeping afterwards has nothing left to report
+	if reported := sweep(t, newReplica(t, mr, c)); len(reported) != 0 {
+		t.Errorf("second sweep reported %+v", reported)
+	}
+}
+
+func TestDisconnectAfterSweepIsNotReportedTwice(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := &clock{now: time.Unix(1_700_000_000, 0)}
+	tracker := newReplica(t, mr, c)
+
+	// The connection's entry lapses, say while Redis was unreachable
+	client := connect(t, tracker, "alice")
+	c.advance(connectionTTL + time.Second)
+
+	if reported := sweep(t, tracker); len(reported) != 1 {
+		t.Fatalf("sweep reported %+v, want alice going offline", reported)
+	}
+
+	wentOffline, _, err := tracker.Disconnect(client)
+	if err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+	if wentOffline {
+		t.Error("Disconnect reported alice offline again after the sweep did")
+	}
+}
+
+func TestLastSeenKeepsTheLatestTime(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := &clock{now: time.Unix(1_700_000_000, 0)}
+	crashed := newReplica(t, mr, c)
+	survivor := newReplica(t, mr, c)
+
+	connect(t, crashed, "alice")
+	phone := connect(t, survivor, "alice")
+
+	// Alice closes the app on her phone after the other replica crashed, but
+	// before its connection expired, so she still looks online
+	c.advance(heartbeatPeriod)
+	wentOffline, disconnectedAt, err := survivor.Disconnect(phone)
+	if err != nil || wentOffline {
+		t.Fatalf("Disconnect = %v, %v; want alice still online", wentOffline, err)
+	}
+
+	c.advance(connectionTTL)
+	reported := sweep(t, survivor)
+	if len(reported) != 1 || !reported[0].LastSeen.Equal(disconnectedAt) {
+		t.Fatalf("sweep reported %+v, want alice last seen when she disconnected, %v", reported, disconnectedAt)
+	}
+}
@@ -0,0 +1,150 @@
+// Package protocol defines the typed event envelope spoken over message-service's WebSocket
+package protocol
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Version is the protocol version this server speaks. Frames without a version are treated as version 1.
+const Version = 1
+
+// Events sent by clients
+const (
+	TypeMessageSend      = "message.send"
+	TypeMessageEdit      = "message.edit"
+	TypeMessageDelete    = "message.delete"
+	TypeTypingStart      = "typing.start"
+	TypeTypingStop       = "typing.stop"
+	TypeReceiptDelivered = "receipt.delivered"
+	TypeReceiptRead      = "receipt.read"
+	TypePresence         = "presence"
+)
+
+// Events sent by the server
+const (
+	TypeAck                 = "ack"
+	TypeError               = "error"
+	TypeMessageNew          = "message.new"
+	TypeMessageEdited       = "message.edited"
+	TypeMessageDeleted      = "message.deleted"
+	TypeMessagesUndelivered = "messages.undelivered"
+	TypeTyping              = "typing"
+)
+
+// Error codes returned in error events
+const (
+	CodeInvalidFrame          = "invalid_frame"
+	CodeInvalidPayload        = "invalid_payload"
+	CodeUnsupportedVersion    = "unsupported_version"
+	CodeUnknownType           = "unknown_type"
+	CodeForbidden             = "forbidden"
+	CodeNotFound              = "not_found"
+	CodeContentRejected       = "content_rejected"
+	CodeModerationUnavailable = "moderation_unavailable"
+	CodeInternal              = "internal_error"
+)
+
+// Envelope wraps every frame a client sends. ID is generated by the client and
+// echoed back in the matching ack or error; for message.send it also makes
+// retries idempotent.
+type Envelope struct {
+	V       int             `json:"v"`
+	Type    string          `json:"type"`
+	ID      string          `json:"id"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Event wraps every frame the server sends. ID is set on acks and errors to the
+// ID of the client event they answer.
+type Event struct {
+	V       int    `json:"v"`
+	Type    string `json:"type"`
+	ID      string `json:"id,omitempty"`
+	Payload any    `json:"payload,omitempty"`
+}
+
+// NewEvent builds a server event of the current protocol version
+func NewEvent(eventType string, payload any) Event {
+	return Event{V: Version, Type: eventType, Payload: payload}
+}
+
+// Ack answers a client event that succeeded
+func Ack(id string, result any) Event {
+	return Event{V: Version, Type: TypeAck, ID: id, Payload: result}
+}
+
+// Error describes why a client event was rejected
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// NewError builds an Error with the given code
+func NewError(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Fail answers a client event that was rejected
+func Fail(id string, err *Error) Event {
+	return Event{V: Version, Type: TypeError, ID: id, Payload: err}
+}
+
+// SendMessage is the payload of message.send
+type SendMessage struct {
+	ConversationID   int    `json:"conversation_id"`
+	Content          string `json:"content"`
+	ReplyToMessageID *int   `json:"reply_to_message_id"`
+}
+
+// EditMessage is the payload of message.edit
+type EditMessage struct {
+	MessageID int    `json:"message_id"`
+	Content   string `json:"content"`
+}
+
+// DeleteMessage is the payload of message.delete. ForEveryone replaces the
+// message with a tombstone for all participants; otherwise it is only hidden
+// from the sender's own history.
+type DeleteMessage struct {
+	MessageID   int  `json:"message_id"`
+	ForEveryone bool `json:"for_everyone"`
+}
+
+// MessageDeleted is the payload of the message.deleted event. For a delete that
+// only applies to the user, it is sent to that user's own devices.
+type MessageDeleted struct {
+	MessageID      int  `json:"message_id"`
+	ConversationID int  `json:"conversation_id"`
+	ForEveryone    bool `json:"for_everyone"`
+}
+
+// Typing is the payload of typing.start and typing.stop, and of the typing event
+// relayed to other participants
+type Typing struct {
+	ConversationID int    `json:"conversation_id"`
+	UserID         string `json:"user_id,omitempty"`
+	Typing         bool   `json:"typing"`
+}
+
+// Receipt is the payload of receipt.delivered and receipt.read. Sent by a
+// client it acknowledges everything in the conversation up to MessageID; relayed
+// by the server UserID says whose devices received or read them.
+type Receipt struct {
+	ConversationID int    `json:"conversation_id"`
+	UserID         string `json:"user_id,omitempty"`
+	MessageID      int    `json:"message_id"`
+}
+
+// Presence is the payload of presence events pushed to a user's connections
+// when they come online or go offline. A client sends it with only UserID set
+// to look up a connection's presence, which comes back in the ack.
+type Presence struct {
+	UserID   string     `json:"user_id,omitempty"`
+	Status   string     `json:"status"`
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+}
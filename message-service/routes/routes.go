@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joy095/message-service/controllers"
+	"github.com/joy095/message-service/hub"
+	"github.com/joy095/message-service/middlewares/auth"
+	"github.com/joy095/message-service/presence"
+)
+
+func RegisterRoutes(router *gin.Engine, h *hub.Hub, tracker *presence.Tracker) {
+	messageController := controllers.NewMessageController()
+	conversationController := controllers.NewConversationController(h)
+	socketController := controllers.NewSocketController(h, tracker)
+	presenceController := controllers.NewPresenceController(tracker)
+
+	// WebSocket authenticates its own handshake, since browsers can't set headers on it
+	router.GET("/ws", socketController.ServeWs)
+
+	// Protected routes
+	protected := router.Group("/")
+	protected.Use(auth.AuthMiddleware())
+	{
+		// Conversation routes
+		protected.GET("/conversations", conversationController.ListConversations)
+		protected.POST("/conversations/direct", conversationController.CreateDirectConversation)
+		protected.POST("/conversations/group", conversationController.CreateGroupConversation)
+		protected.GET("/conversations/:id", conversationController.GetConversation)
+		protected.PUT("/conversations/:id", conversationController.RenameConversation)
+		protected.PUT("/conversations/:id/read", conversationController.MarkRead)
+		protected.POST("/conversations/:id/leave", conversationController.LeaveConversation)
+
+		// Membership routes
+		protected.POST("/conversations/:id/participants", conversationController.AddParticipants)
+		protected.DELETE("/conversations/:id/participants/:user_id", conversationController.RemoveParticipant)
+		protected.PUT("/conversations/:id/participants/:user_id/role", conversationController.SetParticipantRole)
+
+		// Moderation routes
+		protected.PUT("/conversations/:id/moderation", conversationController.SetModerationPolicy)
+		protected.GET("/conversations/:id/flagged", conversationController.GetFlaggedMessages)
+
+		// Message routes
+		protected.GET("/conversations/:id/messages", messageController.GetMessages)
+		protected.GET("/messages/:id/thread", messageController.GetThread)
+		protected.GET("/messages/:id/edits", messageController.GetMessageEdits)
+
+		// Presence routes
+		protected.GET("/presence/:user_id", presenceController.GetPresence)
+	}
+}
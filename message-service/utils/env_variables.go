@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"os"
+	"time"
+
+	"github.com/joy095/message-service/config"
+)
+
+func init() {
+	config.LoadEnv()
+}
+
+// GetJWTSecret returns the secret shared with identity_service for signing
+// access tokens, or nil if JWT_SECRET is not set. There is no fallback: a
+// known secret would let anyone forge a user's token.
+func GetJWTSecret() []byte {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil
+	}
+	return []byte(secret)
+}
+
+// GetMessageEditWindow returns how long after sending a message its sender may still edit it
+func GetMessageEditWindow() time.Duration {
+	window, err := time.ParseDuration(os.Getenv("MESSAGE_EDIT_WINDOW"))
+	if err != nil || window <= 0 {
+		return 15 * time.Minute
+	}
+	return window
+}
@@ -0,0 +1,69 @@
+// Package wordfilter asks word_filter_service whether chat content is acceptable
+package wordfilter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/joy095/word-filter/client"
+)
+
+// ErrUnavailable is returned when word_filter_service can't be reached or
+// answers with something other than a verdict
+var ErrUnavailable = client.ErrUnavailable
+
+// ErrInvalidMaskStyle is returned by ValidateConfig when WORD_FILTER_MASK_STYLE
+// isn't one of the styles word_filter_service knows
+var ErrInvalidMaskStyle = errors.New("invalid WORD_FILTER_MASK_STYLE")
+
+// maskStyles are the styles word_filter_service accepts. An unset style leaves
+// the service's default, masking every character.
+var maskStyles = map[string]bool{"": true, "full": true, "first_letter": true, "fixed": true}
+
+// ValidateConfig checks the filter's settings, so a typo stops the service at
+// startup instead of failing every message
+func ValidateConfig() error {
+	if style := os.Getenv("WORD_FILTER_MASK_STYLE"); !maskStyles[style] {
+		return fmt.Errorf("%w: %q, want full, first_letter or fixed", ErrInvalidMaskStyle, style)
+	}
+	return nil
+}
+
+// filter is created on first use, after the environment has been loaded. It
+// always fails closed; callers decide what to do with FailOpen so they can
+// log what they let through.
+var filter = sync.OnceValue(func() *client.Client {
+	config := client.ConfigFromEnv()
+	config.FailOpen = false
+	return client.New(config)
+})
+
+// Verdict is the filter's opinion of a piece of text. Censored is the text
+// with every offending word masked.
+type Verdict struct {
+	ContainsBadWords bool
+	Censored         string
+}
+
+// Check sends text to word_filter_service's /censor endpoint
+func Check(text string) (*Verdict, error) {
+	response, err := filter().Censor(context.Background(), client.CensorRequest{
+		Text:      text,
+		MaskStyle: os.Getenv("WORD_FILTER_MASK_STYLE"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Verdict{ContainsBadWords: response.ContainsBadWords, Censored: response.Censored}, nil
+}
+
+// FailOpen reports whether messages should be delivered unchecked while the
+// filter is unavailable. It defaults to false, refusing them instead.
+func FailOpen() bool {
+	failOpen, err := strconv.ParseBool(os.Getenv("WORD_FILTER_FAIL_OPEN"))
+	return err == nil && failOpen
+}
@@ -3,29 +3,159 @@ package badwords
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/joy095/word-filter/logger"
+	"github.com/joy095/word-filter/metrics"
 )
 
 // BadWordRequest represents a request to check text for bad words
 type BadWordRequest struct {
-	Text string `json:"text" binding:"required"`
+	Text          string         `json:"text" binding:"required"`
+	Mode          string         `json:"mode" binding:"omitempty,oneof=word substring"`
+	Normalization *Normalization `json:"normalization"`
+	Languages     []string       `json:"languages" binding:"omitempty,dive,required"`
+	Filter
 }
 
-// BadWordResponse represents the response from a bad word check
+// Filter narrows which terms count, so callers can be strict in one place and
+// lenient in another. The zero value counts everything.
+type Filter struct {
+	MinSeverity int      `json:"minSeverity" binding:"omitempty,min=1,max=3"`
+	Categories  []string `json:"categories" binding:"omitempty,dive,oneof=profanity slur sexual violence spam"`
+}
+
+// counts reports whether a match passes the filter
+func (f Filter) counts(m Match) bool {
+	if m.Severity < f.MinSeverity {
+		return false
+	}
+	return len(f.Categories) == 0 || slices.Contains(f.Categories, m.Category)
+}
+
+// BadWordResponse represents the response from a bad word check. Match is
+// the most severe bad word found, naming the list entry that fired.
+// Categories counts the hits in each category, and Score adds up the
+// severity of every hit. Flags are the spam heuristics, reported whether or
+// not the text has bad words.
 type BadWordResponse struct {
-	ContainsBadWords bool `json:"containsBadWords"`
+	ContainsBadWords bool                    `json:"containsBadWords"`
+	Languages        []string                `json:"languages"`
+	Match            *Match                  `json:"match,omitempty"`
+	Categories       map[string]CategoryHits `json:"categories"`
+	Score            int                     `json:"score"`
+	Flags            Flags                   `json:"flags"`
+}
+
+// CategoryHits summarizes the matches in one category
+type CategoryHits struct {
+	Hits        int `json:"hits"`
+	MaxSeverity int `json:"maxSeverity"`
 }
 
-// badWords is a list of bad words or patterns loaded from a text file.
-var badWords []string
+// Errors returned when looking up or changing the lists
+var (
+	ErrUnknownLanguage = errors.New("unknown language")
+	ErrInvalidLanguage = errors.New("language must be a lowercase language code")
+	ErrDuplicateWord   = errors.New("word is already on the list")
+	ErrWordNotFound    = errors.New("word is not on the list")
+	// A list file without words doesn't load, so the last word can't be removed
+	ErrLastWord = errors.New("a list must keep at least one word")
+)
+
+// languagePattern is what a language code, and so a dictionary file name, may look like
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// Options controls how text is matched against the dictionaries
+type Options struct {
+	Mode   string
+	Stages Stage
+	// Languages whose dictionaries are checked; empty means guess from the text
+	Languages []string
+	Filter    Filter
+}
+
+// lists holds the entries of each language's word list, keyed by language code
+var lists = map[string][]Entry{}
+
+// allowlists holds each language's allowlist: words never flagged, whatever they contain
+var allowlists = map[string][]string{}
+
+// files records which file each language was loaded from, so changes can be written back
+var files = map[string]string{}
+
+// dictionaryDir is where lists for new languages are saved
+var dictionaryDir = "badwords"
+
+// listMu serializes changes to lists and their files so each rebuild sees a consistent list
+var listMu sync.Mutex
+
+// dictionaries maps each language to its compiled lists. Readers load it
+// without locking; every change to a list stores a fresh map.
+var dictionaries atomic.Pointer[map[string]*dictionary]
+
+func init() {
+	dictionaries.Store(&map[string]*dictionary{})
+}
+
+// rebuild compiles a language's lists and swaps them in. Callers must hold listMu.
+func rebuild(language string) {
+	d := newDictionary(language, lists[language], allowlists[language])
+	// Compile the default pipeline up front so requests don't wait for it
+	d.compile(AllStages)
+
+	next := maps.Clone(*dictionaries.Load())
+	next[language] = d
+	dictionaries.Store(&next)
+}
+
+// LoadDictionaries loads every <language>.txt file in dir as that language's
+// list, along with its <language>.allow allowlist if there is one, and
+// returns the languages loaded. The domain blocklist, domains.block, is
+// loaded from dir too if it's there.
+func LoadDictionaries(dir string) ([]string, error) {
+	logger.InfoLogger.Info("LoadDictionaries called")
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no dictionaries found in %s", dir)
+	}
 
-// LoadBadWords loads bad words from a text file.
-// Each line in the file represents a bad word or pattern.
-func LoadBadWords(filename string) (bool, error) {
+	listMu.Lock()
+	dictionaryDir = dir
+	listMu.Unlock()
+
+	var languages []string
+	for _, file := range paths {
+		language := strings.TrimSuffix(filepath.Base(file), ".txt")
+		if _, err := LoadBadWords(language, file); err != nil {
+			return languages, fmt.Errorf("loading %s: %w", file, err)
+		}
+		if err := loadAllowlistFor(dir, language); err != nil {
+			return languages, err
+		}
+		languages = append(languages, language)
+	}
+
+	if err := loadBlockedDomainsFrom(dir); err != nil {
+		return languages, err
+	}
+	return languages, nil
+}
+
+// LoadBadWords loads a language's bad words from a text file, replacing its
+// current list. Each line in the file represents a bad word or pattern.
+func LoadBadWords(language, filename string) (bool, error) {
 	logger.InfoLogger.Info("LoadBadWords called")
 
 	data, err := os.ReadFile(filename)
@@ -33,74 +163,254 @@ func LoadBadWords(filename string) (bool, error) {
 		return false, err
 	}
 
-	// Split the file content into lines
-	badWords = strings.Split(string(data), "\n")
+	// A file that doesn't parse leaves the current list in place
+	entries, err := parseList(data)
+	if err != nil {
+		return false, err
+	}
+
+	listMu.Lock()
+	defer listMu.Unlock()
+
+	lists[language] = entries
+	files[language] = filename
+	rebuild(language)
+
+	fmt.Printf("Loaded %d %s bad words from text file\n", len(entries), language)
+	return true, nil
+}
+
+// LoadAllowlist loads a language's allowlist from a text file, replacing its
+// current allowlist. Each line holds one word that is never flagged.
+func LoadAllowlist(language, filename string) error {
+	logger.InfoLogger.Info("LoadAllowlist called")
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	words, err := parseAllowlist(data)
+	if err != nil {
+		return err
+	}
+
+	listMu.Lock()
+	defer listMu.Unlock()
+
+	allowlists[language] = words
+	rebuild(language)
+	return nil
+}
+
+// loadAllowlistFor loads <language>.allow from dir, or drops the language's
+// allowlist if the file doesn't exist
+func loadAllowlistFor(dir, language string) error {
+	filename := filepath.Join(dir, language+".allow")
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		listMu.Lock()
+		defer listMu.Unlock()
+
+		if allowlists[language] != nil {
+			delete(allowlists, language)
+			rebuild(language)
+		}
+		return nil
+	}
+	if err := LoadAllowlist(language, filename); err != nil {
+		return fmt.Errorf("loading %s: %w", filename, err)
+	}
+	return nil
+}
+
+// selectDictionaries returns the dictionaries named by opts, or those guessed
+// from the text when it names none, along with their languages
+func selectDictionaries(text string, opts Options) ([]string, []*dictionary, error) {
+	available := *dictionaries.Load()
+
+	languages := opts.Languages
+	if len(languages) == 0 {
+		languages = guessLanguages(text, slices.Collect(maps.Keys(available)), opts.Stages)
+	}
 
-	// Trim whitespace and remove empty lines
-	for i := 0; i < len(badWords); i++ {
-		badWords[i] = strings.TrimSpace(badWords[i])
-		if badWords[i] == "" {
-			badWords = slices.Delete(badWords, i, i+1)
-			i-- // Adjust index after removing an empty line
+	selected := make([]*dictionary, 0, len(languages))
+	for _, language := range languages {
+		d, ok := available[language]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnknownLanguage, language)
 		}
+		selected = append(selected, d)
 	}
+	return languages, selected, nil
+}
 
-	fmt.Printf("Loaded %d bad words from text file\n", len(badWords))
-	return true, nil
+// ContainsBadWords checks if the input text contains any bad words. In word
+// mode, the default, a term only counts as a whole word or phrase; in
+// substring mode it also counts inside other words. Text is normalized with
+// the given stages before matching.
+func ContainsBadWords(text string, opts Options) (bool, error) {
+	_, selected, err := selectDictionaries(text, opts)
+	if err != nil {
+		return false, err
+	}
+	return firstMatch(selected, text, opts) != nil, nil
 }
 
-// ContainsBadWords checks if the input text contains any bad words.
-// It now checks if the exact word matches any word in the bad words list.
-func ContainsBadWords(text string) bool {
+// firstMatch returns the first bad word found by the selected dictionaries, or nil
+func firstMatch(selected []*dictionary, text string, opts Options) *Match {
 	logger.InfoLogger.Info("ContainsBadWords called")
-	// Convert input to lowercase and split into words
-	words := strings.Fields(strings.ToLower(text))
-
-	// Check each word against the bad words list
-	for _, word := range words {
-		// Remove any punctuation from the word
-		word = strings.Trim(word, ".,!?;:\"'()[]{}")
-
-		// Check if this word is in the bad words list
-		for _, badWord := range badWords {
-			if strings.ToLower(badWord) == word {
-				logger.InfoLogger.Infof("Bad word detected: %s\n", word)
-				fmt.Printf("Bad word detected: %s\n", word)
+
+	units := normalize(text, opts.Stages)
+
+	var first *Match
+	for _, d := range selected {
+		d.check(text, units, opts.Stages, opts.Mode, func(m Match) bool {
+			if !opts.Filter.counts(m) {
 				return true
 			}
+			first = &m
+			return false
+		}, nil)
+		if first != nil {
+			logger.InfoLogger.Infof("Bad word detected by rule %s:%s", first.Language, first.Rule)
+			return first
 		}
 	}
-	return false
+	return nil
 }
 
-// CheckText checks if the input text contains any bad words and returns a response.
-func CheckText(text string) BadWordResponse {
-	return BadWordResponse{
-		ContainsBadWords: ContainsBadWords(text),
+// FindMatches returns every bad word in text with its position, along with
+// the matches that allow rules let through and the languages that were checked
+func FindMatches(text string, opts Options) (matches, allowed []Match, languages []string, err error) {
+	languages, selected, err := selectDictionaries(text, opts)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+
+	units := normalize(text, opts.Stages)
+	for _, d := range selected {
+		d.check(text, units, opts.Stages, opts.Mode, func(m Match) bool {
+			if opts.Filter.counts(m) {
+				matches = append(matches, m)
+			}
+			return true
+		}, func(m Match) {
+			allowed = append(allowed, m)
+		})
+	}
+	return leftmostLongest(matches), leftmostLongest(allowed), languages, nil
 }
 
-// AddBadWord adds a new bad word to the list.
-func AddBadWord(badWord string) (bool, error) {
-	if badWord == "" {
-		return false, errors.New("bad word must not be empty")
+// recordHits counts the matches of a request towards the term metrics
+func recordHits(matches []Match) {
+	for _, m := range matches {
+		metrics.RecordHit(m.Language, m.Rule, m.Category)
 	}
-	badWords = append(badWords, badWord)
-	return true, nil
 }
 
-// RemoveBadWord removes a bad word from the list.
-func RemoveBadWord(badWord string) bool {
-	for i, bw := range badWords {
-		if bw == badWord {
-			badWords = slices.Delete(badWords, i, i+1)
-			return true
+// CheckText checks if the request's text contains any bad words and returns a response.
+func CheckText(req BadWordRequest) (BadWordResponse, error) {
+	matches, _, languages, err := FindMatches(req.Text, Options{
+		Mode:      req.Mode,
+		Stages:    req.Normalization.Stages(),
+		Languages: req.Languages,
+		Filter:    req.Filter,
+	})
+	if err != nil {
+		return BadWordResponse{}, err
+	}
+	recordHits(matches)
+
+	response := BadWordResponse{
+		ContainsBadWords: len(matches) > 0,
+		Languages:        languages,
+		Categories:       map[string]CategoryHits{},
+		Flags:            DetectFlags(req.Text),
+	}
+	for i, m := range matches {
+		hits := response.Categories[m.Category]
+		hits.Hits++
+		hits.MaxSeverity = max(hits.MaxSeverity, m.Severity)
+		response.Categories[m.Category] = hits
+		response.Score += m.Severity
+
+		if response.Match == nil || m.Severity > response.Match.Severity {
+			response.Match = &matches[i]
 		}
 	}
-	return false
+
+	if response.ContainsBadWords {
+		logger.InfoLogger.Infof("Bad word detected by rule %s:%s", response.Match.Language, response.Match.Rule)
+	}
+	return response, nil
+}
+
+// AddBadWord adds a new entry to a language's list, creating the list if
+// needed. The list file is rewritten before the change takes effect.
+func AddBadWord(language string, entry Entry) error {
+	entry = entry.withDefaults()
+	entry.Word = strings.TrimSpace(entry.Word)
+	if err := entry.validate(); err != nil {
+		return err
+	}
+	if !languagePattern.MatchString(language) {
+		return ErrInvalidLanguage
+	}
+
+	listMu.Lock()
+	defer listMu.Unlock()
+
+	if slices.ContainsFunc(lists[language], func(e Entry) bool { return strings.EqualFold(e.Word, entry.Word) }) {
+		return ErrDuplicateWord
+	}
+
+	entries := append(slices.Clip(lists[language]), entry)
+	if err := saveList(language, entries); err != nil {
+		return err
+	}
+
+	lists[language] = entries
+	rebuild(language)
+	return nil
+}
+
+// RemoveBadWord removes a bad word from a language's list. The list file is
+// rewritten before the change takes effect.
+func RemoveBadWord(language, badWord string) error {
+	listMu.Lock()
+	defer listMu.Unlock()
+
+	i := slices.IndexFunc(lists[language], func(e Entry) bool { return strings.EqualFold(e.Word, badWord) })
+	if i < 0 {
+		return ErrWordNotFound
+	}
+	if len(lists[language]) == 1 {
+		return ErrLastWord
+	}
+
+	entries := slices.Delete(slices.Clone(lists[language]), i, i+1)
+	if err := saveList(language, entries); err != nil {
+		return err
+	}
+
+	lists[language] = entries
+	rebuild(language)
+	return nil
+}
+
+// ListBadWords returns the current list of bad words for a language.
+func ListBadWords(language string) []string {
+	listMu.Lock()
+	defer listMu.Unlock()
+
+	words := make([]string, len(lists[language]))
+	for i, entry := range lists[language] {
+		words[i] = entry.Word
+	}
+	return words
 }
 
-// ListBadWords returns the current list of bad words.
-func ListBadWords() []string {
-	return badWords
+// Languages returns the languages that have a dictionary
+func Languages() []string {
+	return slices.Sorted(maps.Keys(*dictionaries.Load()))
 }
@@ -0,0 +1,102 @@
+package badwords
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Limits on a single batch, so one request can't tie up every worker
+const (
+	MaxBatchItems     = 100
+	MaxBatchTextBytes = 256 << 10
+	// MaxBatchBodyBytes caps the encoded request, which also carries keys and options
+	MaxBatchBodyBytes = 1 << 20
+)
+
+// Errors returned for a batch that can't be checked
+var (
+	ErrDuplicateKey  = errors.New("batch keys must be unique")
+	ErrBatchTooLarge = errors.New("batch is too large")
+)
+
+// BatchRequest checks several texts at once. Each item is a full check
+// request with a key that names it in the response, such as "displayName" or
+// "bio".
+type BatchRequest struct {
+	Items []BatchItem `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// BatchItem is one keyed text of a batch
+type BatchItem struct {
+	Key string `json:"key" binding:"required,max=100"`
+	BadWordRequest
+}
+
+// BatchResponse holds the result for each key. ContainsBadWords is true if any
+// item contains bad words.
+type BatchResponse struct {
+	ContainsBadWords bool                   `json:"containsBadWords"`
+	Results          map[string]BatchResult `json:"results"`
+}
+
+// BatchResult is the outcome of one item: the check's response, or why the
+// item couldn't be checked
+type BatchResult struct {
+	Result *BadWordResponse `json:"result,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// CheckBatch checks every item of the batch concurrently. An item that fails,
+// for example by naming an unknown language, reports its error without
+// failing the others.
+func CheckBatch(req BatchRequest) (BatchResponse, error) {
+	keys := make(map[string]bool, len(req.Items))
+	total := 0
+	for _, item := range req.Items {
+		if keys[item.Key] {
+			return BatchResponse{}, fmt.Errorf("%w: %s", ErrDuplicateKey, item.Key)
+		}
+		keys[item.Key] = true
+		total += len(item.Text)
+	}
+	if len(req.Items) > MaxBatchItems {
+		return BatchResponse{}, fmt.Errorf("%w: at most %d items", ErrBatchTooLarge, MaxBatchItems)
+	}
+	if total > MaxBatchTextBytes {
+		return BatchResponse{}, fmt.Errorf("%w: at most %d bytes of text", ErrBatchTooLarge, MaxBatchTextBytes)
+	}
+
+	results := make([]BatchResult, len(req.Items))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(req.Items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				response, err := CheckText(req.Items[i].BadWordRequest)
+				if err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+				results[i].Result = &response
+			}
+		}()
+	}
+	for i := range req.Items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	response := BatchResponse{Results: make(map[string]BatchResult, len(req.Items))}
+	for i, item := range req.Items {
+		response.Results[item.Key] = results[i]
+		if results[i].Result != nil && results[i].Result.ContainsBadWords {
+			response.ContainsBadWords = true
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,140 @@
+package badwords
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/joy095/word-filter/logger"
+)
+
+// Mask styles accepted by CensorText
+const (
+	MaskFull        = "full"         // every character becomes the mask character
+	MaskFirstLetter = "first_letter" // the first character is kept, the rest masked
+	MaskFixed       = "fixed"        // the whole word is swapped for a fixed replacement
+)
+
+// maskChar replaces masked characters
+const maskChar = '*'
+
+// DefaultReplacement is used by the fixed mask style when no replacement is given
+const DefaultReplacement = "****"
+
+// CensorRequest represents a request to mask bad words in text
+type CensorRequest struct {
+	Text          string         `json:"text" binding:"required"`
+	Mode          string         `json:"mode" binding:"omitempty,oneof=word substring"`
+	Normalization *Normalization `json:"normalization"`
+	Languages     []string       `json:"languages" binding:"omitempty,dive,required"`
+	MaskStyle     string         `json:"maskStyle" binding:"omitempty,oneof=full first_letter fixed"`
+	Replacement   string         `json:"replacement" binding:"omitempty,max=64"`
+	Filter
+}
+
+// Match is one bad word found in text. Start and End are byte offsets into
+// the original text, RuneStart and RuneEnd the same span counted in runes.
+// End and RuneEnd are exclusive. Rule is the list entry that matched, and for
+// a match an allow rule let through, AllowedBy is the word that covered it.
+type Match struct {
+	Term      string `json:"term"`
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	RuneStart int    `json:"runeStart"`
+	RuneEnd   int    `json:"runeEnd"`
+	Language  string `json:"language"`
+	Rule      string `json:"rule"`
+	Category  string `json:"category"`
+	Severity  int    `json:"severity"`
+	AllowedBy string `json:"allowedBy,omitempty"`
+	AllowRule string `json:"allowRule,omitempty"`
+}
+
+// CensorResponse represents the response from masking bad words
+type CensorResponse struct {
+	ContainsBadWords bool     `json:"containsBadWords"`
+	Languages        []string `json:"languages"`
+	Matches          []Match  `json:"matches"`
+	Allowed          []Match  `json:"allowed"`
+	Censored         string   `json:"censored"`
+}
+
+// Mask returns text with each match replaced according to style. replacement
+// is only used by MaskFixed and defaults to DefaultReplacement. Matches may
+// come in any order; overlapping ones are masked as one span, while adjacent
+// ones stay separate.
+func Mask(text string, matches []Match, style, replacement string) string {
+	if replacement == "" {
+		replacement = DefaultReplacement
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+
+	last := 0
+	for _, s := range maskedSpans(matches) {
+		b.WriteString(text[last:s.start])
+		b.WriteString(maskTerm(text[s.start:s.end], style, replacement))
+		last = s.end
+	}
+	b.WriteString(text[last:])
+
+	return b.String()
+}
+
+// maskedSpans returns the byte spans of the matches sorted by position, with
+// overlapping spans merged
+func maskedSpans(matches []Match) []span {
+	spans := make([]span, 0, len(matches))
+	for _, m := range matches {
+		spans = append(spans, span{start: m.Start, end: m.End})
+	}
+	slices.SortFunc(spans, func(a, b span) int { return a.start - b.start })
+
+	merged := spans[:0]
+	for _, s := range spans {
+		if n := len(merged); n > 0 && s.start < merged[n-1].end {
+			merged[n-1].end = max(merged[n-1].end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// maskTerm masks a single matched word
+func maskTerm(term, style, replacement string) string {
+	switch style {
+	case MaskFixed:
+		return replacement
+	case MaskFirstLetter:
+		first, size := utf8.DecodeRuneInString(term)
+		return string(first) + strings.Repeat(string(maskChar), utf8.RuneCountInString(term[size:]))
+	default:
+		return strings.Repeat(string(maskChar), utf8.RuneCountInString(term))
+	}
+}
+
+// CensorText finds bad words in the request's text and returns them along with a masked copy
+func CensorText(req CensorRequest) (CensorResponse, error) {
+	logger.InfoLogger.Info("CensorText called")
+
+	matches, allowed, languages, err := FindMatches(req.Text, Options{
+		Mode:      req.Mode,
+		Stages:    req.Normalization.Stages(),
+		Languages: req.Languages,
+		Filter:    req.Filter,
+	})
+	if err != nil {
+		return CensorResponse{}, err
+	}
+	recordHits(matches)
+
+	return CensorResponse{
+		ContainsBadWords: len(matches) > 0,
+		Languages:        languages,
+		Matches:          matches,
+		Allowed:          allowed,
+		Censored:         Mask(req.Text, matches, req.MaskStyle, req.Replacement),
+	}, nil
+}
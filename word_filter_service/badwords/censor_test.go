@@ -0,0 +1,103 @@
+package badwords
+
+import "testing"
+
+func TestCensorText(t *testing.T) {
+	loadDictionaries(t)
+
+	tests := []struct {
+		name                     string
+		text                     string
+		full, firstLetter, fixed string
+	}{
+		{"plain", "well shit", "well ****", "well s***", "well ****"},
+		{"two words", "shit fuck", "**** ****", "s*** f***", "**** ****"},
+		{"phrase", "you ass bandit!", "you **********!", "you a*********!", "you ****!"},
+		{"hyphenated", "a fuck-wit", "a ********", "a f*******", "a ****"},
+		// Obfuscated matches are masked over every byte they took in the original text
+		{"spelled out", "f.u.c.k off", "******* off", "f****** off", "**** off"},
+		{"repeated letters", "fuuuuck", "*******", "f******", "****"},
+		{"leet", "sh!t happens", "**** happens", "s*** happens", "**** happens"},
+		{"fullwidth", "ｆｕｃｋ off", "**** off", "ｆ*** off", "**** off"},
+		{"accented", "café fúck", "café ****", "café f***", "café ****"},
+		{"cyrillic", "ѕhіt!", "****!", "ѕ***!", "****!"},
+		{"zero-width split", "fu​ck", "*****", "f****", "****"},
+		{"clean", "a classic cocktail", "a classic cocktail", "a classic cocktail", "a classic cocktail"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for style, want := range map[string]string{MaskFull: tt.full, MaskFirstLetter: tt.firstLetter, MaskFixed: tt.fixed} {
+				response, err := CensorText(CensorRequest{Text: tt.text, MaskStyle: style, Languages: []string{"en"}})
+				if err != nil {
+					t.Fatalf("CensorText: %v", err)
+				}
+				if response.Censored != want {
+					t.Errorf("%s: censored %q, want %q", style, response.Censored, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCensorDefaultsToFull(t *testing.T) {
+	loadDictionaries(t)
+
+	response, err := CensorText(CensorRequest{Text: "oh shit", Languages: []string{"en"}})
+	if err != nil {
+		t.Fatalf("CensorText: %v", err)
+	}
+	if response.Censored != "oh ****" {
+		t.Errorf("censored %q, want %q", response.Censored, "oh ****")
+	}
+}
+
+func TestMask(t *testing.T) {
+	const text = "abcdefghij"
+	match := func(start, end int) Match {
+		return Match{Term: text[start:end], Start: start, End: end}
+	}
+
+	tests := []struct {
+		name        string
+		matches     []Match
+		style       string
+		replacement string
+		want        string
+	}{
+		{"none", nil, MaskFull, "", "abcdefghij"},
+		{"fixed with its default", []Match{match(2, 5)}, MaskFixed, "", "ab****fghij"},
+		{"fixed replacement", []Match{match(2, 5)}, MaskFixed, "[removed]", "ab[removed]fghij"},
+		{"replacement only applies to fixed", []Match{match(2, 5)}, MaskFull, "[removed]", "ab***fghij"},
+		{"adjacent full", []Match{match(0, 3), match(3, 6)}, MaskFull, "", "******ghij"},
+		{"adjacent first letter", []Match{match(0, 3), match(3, 6)}, MaskFirstLetter, "", "a**d**ghij"},
+		{"adjacent fixed", []Match{match(0, 3), match(3, 6)}, MaskFixed, "#", "##ghij"},
+		{"overlapping full", []Match{match(0, 4), match(2, 6)}, MaskFull, "", "******ghij"},
+		{"overlapping first letter", []Match{match(0, 4), match(2, 6)}, MaskFirstLetter, "", "a*****ghij"},
+		{"overlapping fixed", []Match{match(0, 4), match(2, 6)}, MaskFixed, "#", "#ghij"},
+		{"nested", []Match{match(1, 8), match(2, 4)}, MaskFirstLetter, "", "ab******ij"},
+		{"out of order", []Match{match(6, 8), match(0, 2)}, MaskFull, "", "**cdef**ij"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mask(text, tt.matches, tt.style, tt.replacement); got != tt.want {
+				t.Errorf("Mask = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskMultiByte(t *testing.T) {
+	// "ñandú" is 7 bytes and 5 runes; the mask replaces runes, not bytes
+	text := "el ñandú corre"
+	matches := []Match{{Term: "ñandú", Start: 3, End: 10}}
+
+	for style, want := range map[string]string{
+		MaskFull:        "el ***** corre",
+		MaskFirstLetter: "el ñ**** corre",
+		MaskFixed:       "el **** corre",
+	} {
+		if got := Mask(text, matches, style, ""); got != want {
+			t.Errorf("%s: Mask = %q, want %q", style, got, want)
+		}
+	}
+}
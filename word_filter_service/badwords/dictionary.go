@@ -0,0 +1,119 @@
+package badwords
+
+import (
+	"sync"
+)
+
+// Kinds of rule that can stop a term from being flagged
+const (
+	AllowRuleAllowlist = "allowlist" // the language's allowlist
+	AllowRuleException = "exception" // an exception on the term's own entry
+)
+
+// dictionary is the compiled form of one version of a language's lists. Each
+// combination of normalization stages needs its own matchers; they're built on
+// first use and kept until the lists change.
+type dictionary struct {
+	language  string
+	entries   []Entry
+	allowlist []string
+
+	// compiled maps each Stage combination to a func() *compiledDictionary that
+	// builds its matchers once. Checks only wait on a combination that is
+	// still being compiled, never on the rest of the dictionary.
+	compiled sync.Map
+}
+
+// compiledDictionary holds the matchers for one set of stages
+type compiledDictionary struct {
+	block *Matcher
+	allow *Matcher
+	// For each allow pattern, the entry it exempts, or -1 for the allowlist
+	exempts []int
+	// For each allow pattern, the word it came from
+	allowWords []string
+}
+
+// allowed is an occurrence of an allowlist word or exception in the text
+type allowed struct {
+	span
+	pattern int
+}
+
+func newDictionary(language string, entries []Entry, allowlist []string) *dictionary {
+	return &dictionary{
+		language:  language,
+		entries:   entries,
+		allowlist: allowlist,
+	}
+}
+
+// compile returns the matchers for a set of stages, building them on first use
+func (d *dictionary) compile(stages Stage) *compiledDictionary {
+	build, ok := d.compiled.Load(stages)
+	if !ok {
+		build, _ = d.compiled.LoadOrStore(stages, sync.OnceValue(func() *compiledDictionary {
+			return d.build(stages)
+		}))
+	}
+	return build.(func() *compiledDictionary)()
+}
+
+// build compiles the matchers for a set of stages
+func (d *dictionary) build(stages Stage) *compiledDictionary {
+	words := make([]string, len(d.entries))
+	c := &compiledDictionary{}
+	for i, entry := range d.entries {
+		words[i] = entry.Word
+		for _, exception := range entry.Exceptions {
+			c.allowWords = append(c.allowWords, exception)
+			c.exempts = append(c.exempts, i)
+		}
+	}
+	for _, word := range d.allowlist {
+		c.allowWords = append(c.allowWords, word)
+		c.exempts = append(c.exempts, -1)
+	}
+
+	c.block = NewMatcher(words, stages)
+	c.allow = NewMatcher(c.allowWords, stages)
+	return c
+}
+
+// check finds the dictionary's terms in normalized text. Matches that an
+// allowlist word or exception covers go to onAllowed; the rest go to
+// onMatch, which can stop the search by returning false.
+func (d *dictionary) check(text string, units []unit, stages Stage, mode string, onMatch func(Match) bool, onAllowed func(Match)) {
+	c := d.compile(stages)
+
+	// Allow rules cover their words wherever they appear, even inside longer words
+	var allowances []allowed
+	c.allow.scan(units, ModeSubstring, func(s span, pattern int) bool {
+		allowances = append(allowances, allowed{span: s, pattern: pattern})
+		return true
+	})
+
+	c.block.scan(units, mode, func(s span, term int) bool {
+		match := newMatch(text, units, s)
+		match.Language = d.language
+		match.Rule = d.entries[term].Word
+		match.Category = d.entries[term].Category
+		match.Severity = d.entries[term].Severity
+
+		for _, a := range allowances {
+			exempt := c.exempts[a.pattern]
+			if a.start <= s.start && s.end <= a.end && (exempt == -1 || exempt == term) {
+				match.AllowedBy = c.allowWords[a.pattern]
+				match.AllowRule = AllowRuleAllowlist
+				if exempt != -1 {
+					match.AllowRule = AllowRuleException
+				}
+				if onAllowed != nil {
+					onAllowed(match)
+				}
+				return true
+			}
+		}
+		return onMatch(match)
+	})
+}
@@ -0,0 +1,135 @@
+package badwords
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentCompile(t *testing.T) {
+	d := newDictionary("en", []Entry{{Word: "badword"}}, []string{"class"})
+	text := "a badword in class"
+
+	// Every stage combination compiles at once, each from several checks
+	var wg sync.WaitGroup
+	for stages := range AllStages + 1 {
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var rules []string
+				d.check(text, normalize(text, stages), stages, ModeWord,
+					func(m Match) bool { rules = append(rules, m.Rule); return true }, nil)
+				if len(rules) != 1 || rules[0] != "badword" {
+					t.Errorf("stages %05b: matched %v, want [badword]", stages, rules)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	// Each combination was compiled once and is reused afterwards
+	first := d.compile(StageNFKC)
+	if d.compile(StageNFKC) != first {
+		t.Error("compile built StageNFKC's matchers again")
+	}
+}
+
+func TestScunthorpe(t *testing.T) {
+	loadDictionaries(t)
+
+	tests := []struct {
+		text string
+		// The match each allow rule let through, in substring mode
+		allowed []Match
+	}{
+		{"classic", []Match{{Term: "ass", Start: 2, End: 5, Rule: "ass", AllowedBy: "class", AllowRule: AllowRuleException}}},
+		{"Essex", []Match{{Term: "ssex", Start: 1, End: 5, Rule: "sex", AllowedBy: "essex", AllowRule: AllowRuleException}}},
+		{"grass", []Match{{Term: "ass", Start: 2, End: 5, Rule: "ass", AllowedBy: "grass", AllowRule: AllowRuleException}}},
+		{"assassin", []Match{
+			{Term: "ass", Start: 0, End: 3, Rule: "ass", AllowedBy: "assassin", AllowRule: AllowRuleException},
+			{Term: "ass", Start: 3, End: 6, Rule: "ass", AllowedBy: "assassin", AllowRule: AllowRuleException},
+		}},
+		{"cocktail", []Match{{Term: "cock", Start: 0, End: 4, Rule: "cock", AllowedBy: "cocktail", AllowRule: AllowRuleException}}},
+		{"Scunthorpe", []Match{{Term: "cunt", Start: 1, End: 5, Rule: "cunt", AllowedBy: "scunthorpe", AllowRule: AllowRuleAllowlist}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			for _, mode := range []string{ModeWord, ModeSubstring} {
+				matches, allowed, _, err := FindMatches(tt.text, Options{Mode: mode, Stages: AllStages, Languages: []string{"en"}})
+				if err != nil {
+					t.Fatalf("FindMatches: %v", err)
+				}
+				if len(matches) != 0 {
+					t.Errorf("%s mode flagged %+v", mode, matches)
+				}
+				// A term that only appears inside a longer word never fires in word mode
+				if mode == ModeWord {
+					continue
+				}
+
+				if len(allowed) != len(tt.allowed) {
+					t.Fatalf("%s mode allowed %+v, want %d matches", mode, allowed, len(tt.allowed))
+				}
+				for i, want := range tt.allowed {
+					got := allowed[i]
+					if got.Term != want.Term || got.Start != want.Start || got.End != want.End || got.Rule != want.Rule {
+						t.Errorf("allowed match %q [%d,%d) on %q, want %q [%d,%d) on %q", got.Term, got.Start, got.End, got.Rule, want.Term, want.Start, want.End, want.Rule)
+					}
+					if got.AllowedBy != want.AllowedBy || got.AllowRule != want.AllowRule {
+						t.Errorf("allowed by %q (%s), want %q (%s)", got.AllowedBy, got.AllowRule, want.AllowedBy, want.AllowRule)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBareTermsStillMatch(t *testing.T) {
+	loadDictionaries(t)
+
+	tests := []struct {
+		text, rule string
+		start      int
+	}{
+		{"ass", "ass", 0},
+		{"sex", "sex", 0},
+		{"cock", "cock", 0},
+		{"cunt", "cunt", 0},
+		// An allowed word in the same text doesn't cover the term elsewhere
+		{"a classic ass", "ass", 10},
+		{"a cocktail and a cock", "cock", 17},
+		{"SCUNTHORPE cunt", "cunt", 11},
+	}
+	for _, tt := range tests {
+		for _, mode := range []string{ModeWord, ModeSubstring} {
+			matches, _, _, err := FindMatches(tt.text, Options{Mode: mode, Stages: AllStages, Languages: []string{"en"}})
+			if err != nil {
+				t.Fatalf("FindMatches(%q): %v", tt.text, err)
+			}
+			if len(matches) != 1 || matches[0].Rule != tt.rule || matches[0].Start != tt.start {
+				t.Errorf("%s mode FindMatches(%q) = %+v, want one match on %s at %d", mode, tt.text, matches, tt.rule, tt.start)
+				continue
+			}
+			if matches[0].AllowedBy != "" || matches[0].AllowRule != "" {
+				t.Errorf("%s mode FindMatches(%q) reported a flagged match as allowed: %+v", mode, tt.text, matches[0])
+			}
+		}
+	}
+}
+
+func TestExceptionOnlyCoversItsEntry(t *testing.T) {
+	d := newDictionary("en", []Entry{{Word: "ass", Exceptions: []string{"class"}}, {Word: "las"}}, nil)
+	text := "class"
+
+	var matched, allowed []string
+	d.check(text, normalize(text, AllStages), AllStages, ModeSubstring,
+		func(m Match) bool { matched = append(matched, m.Rule); return true },
+		func(m Match) { allowed = append(allowed, m.Rule) })
+
+	if len(allowed) != 1 || allowed[0] != "ass" {
+		t.Errorf("allowed %v, want [ass]", allowed)
+	}
+	if len(matched) != 1 || matched[0] != "las" {
+		t.Errorf("matched %v, want [las]: the exception belongs to ass", matched)
+	}
+}
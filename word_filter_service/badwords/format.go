@@ -0,0 +1,177 @@
+package badwords
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Categories a term can be tagged with
+const (
+	CategoryProfanity = "profanity"
+	CategorySlur      = "slur"
+	CategorySexual    = "sexual"
+	CategoryViolence  = "violence"
+	CategorySpam      = "spam"
+)
+
+// Categories lists every category, in the order responses report them
+var Categories = []string{CategoryProfanity, CategorySlur, CategorySexual, CategoryViolence, CategorySpam}
+
+// Severity levels, from mild language to terms that should never be let through
+const (
+	SeverityMild   = 1
+	SeverityStrong = 2
+	SeveritySevere = 3
+)
+
+// What a plain line, without attributes, is tagged as
+const (
+	DefaultCategory = CategoryProfanity
+	DefaultSeverity = SeverityStrong
+)
+
+// Entry is one line of a word list: a term and the rules attached to it.
+//
+// A plain line is just the term, tagged with the default category and
+// severity. Attributes follow it as key=value pairs, separated by semicolons:
+//
+//	ass ; category=profanity ; severity=2 ; except=class,assistant,essex
+type Entry struct {
+	Word     string `json:"word"`
+	Category string `json:"category"`
+	Severity int    `json:"severity"`
+	// Words containing the term that shouldn't be flagged because of it
+	Exceptions []string `json:"exceptions,omitempty"`
+}
+
+// ErrInvalidCategory is returned for a category or severity that isn't one of the known ones
+var ErrInvalidCategory = errors.New("category or severity is not valid")
+
+// ErrInvalidWord is returned for a term or exception that can't be written to a list file
+var ErrInvalidWord = errors.New("words must not be blank or contain ';', ',' or line breaks")
+
+// String formats the entry as a list file line, leaving out attributes that have their default value
+func (e Entry) String() string {
+	line := e.Word
+	if e.Category != DefaultCategory {
+		line += " ; category=" + e.Category
+	}
+	if e.Severity != DefaultSeverity {
+		line += " ; severity=" + strconv.Itoa(e.Severity)
+	}
+	if len(e.Exceptions) > 0 {
+		line += " ; except=" + strings.Join(e.Exceptions, ",")
+	}
+	return line
+}
+
+// withDefaults fills in the category and severity of an entry that has none
+func (e Entry) withDefaults() Entry {
+	if e.Category == "" {
+		e.Category = DefaultCategory
+	}
+	if e.Severity == 0 {
+		e.Severity = DefaultSeverity
+	}
+	return e
+}
+
+// validate checks that the entry survives a round trip through its file
+func (e Entry) validate() error {
+	if !slices.Contains(Categories, e.Category) || e.Severity < SeverityMild || e.Severity > SeveritySevere {
+		return ErrInvalidCategory
+	}
+	if strings.TrimSpace(e.Word) == "" || strings.ContainsAny(e.Word, ";\r\n") {
+		return ErrInvalidWord
+	}
+	for _, exception := range e.Exceptions {
+		if strings.TrimSpace(exception) == "" || strings.ContainsAny(exception, ";,\r\n") {
+			return ErrInvalidWord
+		}
+	}
+	return nil
+}
+
+// parseEntry reads one non-blank line of a word list
+func parseEntry(line string) (Entry, error) {
+	fields := strings.Split(line, ";")
+	entry := Entry{Word: strings.TrimSpace(fields[0]), Category: DefaultCategory, Severity: DefaultSeverity}
+	if entry.Word == "" {
+		return Entry{}, errors.New("missing word")
+	}
+
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return Entry{}, fmt.Errorf("attribute %q is not key=value", strings.TrimSpace(field))
+		}
+
+		value = strings.TrimSpace(value)
+		switch key = strings.TrimSpace(key); key {
+		case "category":
+			if !slices.Contains(Categories, value) {
+				return Entry{}, fmt.Errorf("unknown category %q", value)
+			}
+			entry.Category = value
+		case "severity":
+			severity, err := strconv.Atoi(value)
+			if err != nil || severity < SeverityMild || severity > SeveritySevere {
+				return Entry{}, fmt.Errorf("severity must be %d to %d, got %q", SeverityMild, SeveritySevere, value)
+			}
+			entry.Severity = severity
+		case "except":
+			for exception := range strings.SplitSeq(value, ",") {
+				if exception = strings.TrimSpace(exception); exception != "" {
+					entry.Exceptions = append(entry.Exceptions, exception)
+				}
+			}
+		default:
+			return Entry{}, fmt.Errorf("unknown attribute %q", key)
+		}
+	}
+	return entry, nil
+}
+
+// parseList splits a word list file into its entries, one per line, ignoring
+// blank lines. A list must be valid UTF-8 and have at least one entry.
+func parseList(data []byte) ([]Entry, error) {
+	var entries []Entry
+	for i, line := range strings.Split(string(data), "\n") {
+		if !utf8.ValidString(line) {
+			return nil, fmt.Errorf("line %d is not valid UTF-8", i+1)
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		entry, err := parseEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("list has no words")
+	}
+	return entries, nil
+}
+
+// parseAllowlist splits an allowlist file into its words, one per line,
+// ignoring blank lines. Unlike a word list, an allowlist may be empty.
+func parseAllowlist(data []byte) ([]string, error) {
+	var words []string
+	for i, line := range strings.Split(string(data), "\n") {
+		if !utf8.ValidString(line) {
+			return nil, fmt.Errorf("line %d is not valid UTF-8", i+1)
+		}
+		if word := strings.TrimSpace(line); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words, nil
+}
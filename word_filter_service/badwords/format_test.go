@@ -0,0 +1,158 @@
+package badwords
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		line string
+		want Entry
+	}{
+		{"darn", Entry{Word: "darn", Category: DefaultCategory, Severity: DefaultSeverity}},
+		{"  darn  ", Entry{Word: "darn", Category: DefaultCategory, Severity: DefaultSeverity}},
+		{"two words", Entry{Word: "two words", Category: DefaultCategory, Severity: DefaultSeverity}},
+		{"darn ; severity=1", Entry{Word: "darn", Category: DefaultCategory, Severity: SeverityMild}},
+		{"darn ; category=slur", Entry{Word: "darn", Category: CategorySlur, Severity: DefaultSeverity}},
+		{"darn;category=sexual;severity=3", Entry{Word: "darn", Category: CategorySexual, Severity: SeveritySevere}},
+		{"darn ; severity = 3 ; category = spam", Entry{Word: "darn", Category: CategorySpam, Severity: SeveritySevere}},
+		{"ass ; except=class, grass,,assistant ,", Entry{Word: "ass", Category: DefaultCategory, Severity: DefaultSeverity, Exceptions: []string{"class", "grass", "assistant"}}},
+		{"ass ; except=", Entry{Word: "ass", Category: DefaultCategory, Severity: DefaultSeverity}},
+		// A later attribute overrides an earlier one
+		{"darn ; severity=1 ; severity=3", Entry{Word: "darn", Category: DefaultCategory, Severity: SeveritySevere}},
+	}
+	for _, tt := range tests {
+		got, err := parseEntry(tt.line)
+		if err != nil {
+			t.Errorf("parseEntry(%q): %v", tt.line, err)
+			continue
+		}
+		if got.Word != tt.want.Word || got.Category != tt.want.Category || got.Severity != tt.want.Severity || !slices.Equal(got.Exceptions, tt.want.Exceptions) {
+			t.Errorf("parseEntry(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseEntryErrors(t *testing.T) {
+	tests := []struct {
+		line string
+		// A fragment of the error message
+		want string
+	}{
+		{"; category=slur", "missing word"},
+		{"   ; severity=1", "missing word"},
+		{"darn ; slur", "not key=value"},
+		{"darn ; category=slur ; ", "not key=value"},
+		{"darn ; colour=red", "unknown attribute"},
+		{"darn ; Category=slur", "unknown attribute"},
+		{"darn ; category=hate", "unknown category"},
+		{"darn ; category=Slur", "unknown category"},
+		{"darn ; category=", "unknown category"},
+		{"darn ; severity=0", "severity must be 1 to 3"},
+		{"darn ; severity=4", "severity must be 1 to 3"},
+		{"darn ; severity=-1", "severity must be 1 to 3"},
+		{"darn ; severity=high", "severity must be 1 to 3"},
+		{"darn ; severity=", "severity must be 1 to 3"},
+	}
+	for _, tt := range tests {
+		if _, err := parseEntry(tt.line); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseEntry(%q) error = %v, want one containing %q", tt.line, err, tt.want)
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	entries, err := parseList([]byte("darn\n\n  \nheck ; severity=1\r\nblast ; category=profanity\n"))
+	if err != nil {
+		t.Fatalf("parseList: %v", err)
+	}
+	words := make([]string, len(entries))
+	for i, entry := range entries {
+		words[i] = entry.Word
+	}
+	if !slices.Equal(words, []string{"darn", "heck", "blast"}) {
+		t.Errorf("parseList words = %q, want [darn heck blast]", words)
+	}
+	if entries[1].Severity != SeverityMild {
+		t.Errorf("heck has severity %d, want %d", entries[1].Severity, SeverityMild)
+	}
+
+	for _, tt := range []struct {
+		data, want string
+	}{
+		{"", "list has no words"},
+		{"\n \n", "list has no words"},
+		{"darn\nheck ; severity=9", "line 2"},
+		{"darn\n\xff\n", "line 2 is not valid UTF-8"},
+	} {
+		if _, err := parseList([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseList(%q) error = %v, want one containing %q", tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestEntryRoundTrip(t *testing.T) {
+	for _, entry := range []Entry{
+		{Word: "darn", Category: DefaultCategory, Severity: DefaultSeverity},
+		{Word: "darn", Category: CategoryViolence, Severity: SeverityMild},
+		{Word: "ass", Category: DefaultCategory, Severity: SeveritySevere, Exceptions: []string{"class", "grass"}},
+	} {
+		line := entry.String()
+		parsed, err := parseEntry(line)
+		if err != nil {
+			t.Errorf("parseEntry(%q): %v", line, err)
+			continue
+		}
+		if parsed.Word != entry.Word || parsed.Category != entry.Category || parsed.Severity != entry.Severity || !slices.Equal(parsed.Exceptions, entry.Exceptions) {
+			t.Errorf("%+v came back from %q as %+v", entry, line, parsed)
+		}
+	}
+
+	// Defaults are left out of the line
+	if line := (Entry{Word: "darn", Category: DefaultCategory, Severity: DefaultSeverity}).String(); line != "darn" {
+		t.Errorf("String = %q, want %q", line, "darn")
+	}
+}
+
+func TestEntryValidate(t *testing.T) {
+	tests := []struct {
+		entry Entry
+		want  error
+	}{
+		{Entry{Word: "darn"}.withDefaults(), nil},
+		{Entry{Word: "darn", Category: "hate", Severity: DefaultSeverity}, ErrInvalidCategory},
+		{Entry{Word: "darn", Category: DefaultCategory, Severity: 4}, ErrInvalidCategory},
+		{Entry{Word: "darn", Category: DefaultCategory}, ErrInvalidCategory},
+		{Entry{Word: " ", Category: DefaultCategory, Severity: DefaultSeverity}, ErrInvalidWord},
+		{Entry{Word: "darn ; severity=1", Category: DefaultCategory, Severity: DefaultSeverity}, ErrInvalidWord},
+		{Entry{Word: "darn\nheck", Category: DefaultCategory, Severity: DefaultSeverity}, ErrInvalidWord},
+		{Entry{Word: "ass", Category: DefaultCategory, Severity: DefaultSeverity, Exceptions: []string{"class,grass"}}, ErrInvalidWord},
+		{Entry{Word: "ass", Category: DefaultCategory, Severity: DefaultSeverity, Exceptions: []string{""}}, ErrInvalidWord},
+	}
+	for _, tt := range tests {
+		if err := tt.entry.validate(); !errors.Is(err, tt.want) {
+			t.Errorf("validate(%+v) = %v, want %v", tt.entry, err, tt.want)
+		}
+	}
+}
+
+func TestShippedListsParse(t *testing.T) {
+	paths, err := filepath.Glob("*.txt")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no lists found: %v", err)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parseList(data); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package badwords
+
+import (
+	"slices"
+	"unicode"
+)
+
+// languageScripts maps language codes to the scripts they're written in.
+// Languages not listed here are assumed to use the Latin script.
+var languageScripts = map[string][]*unicode.RangeTable{
+	"ar": {unicode.Arabic},
+	"bg": {unicode.Cyrillic},
+	"bn": {unicode.Bengali},
+	"el": {unicode.Greek},
+	"fa": {unicode.Arabic},
+	"he": {unicode.Hebrew},
+	"hi": {unicode.Devanagari},
+	"ja": {unicode.Hiragana, unicode.Katakana, unicode.Han},
+	"ko": {unicode.Hangul},
+	"mr": {unicode.Devanagari},
+	"ru": {unicode.Cyrillic},
+	"sr": {unicode.Cyrillic},
+	"ta": {unicode.Tamil},
+	"th": {unicode.Thai},
+	"uk": {unicode.Cyrillic},
+	"ur": {unicode.Arabic},
+	"zh": {unicode.Han},
+}
+
+// scriptsOf returns the scripts a language is written in
+func scriptsOf(language string) []*unicode.RangeTable {
+	if scripts, ok := languageScripts[language]; ok {
+		return scripts
+	}
+	return []*unicode.RangeTable{unicode.Latin}
+}
+
+// guessLanguages picks the languages whose scripts appear in text. Script is
+// all it looks at, so every Latin-script language is picked for English text.
+// When homoglyphs are folded, lookalike letters count as Latin too, so a word
+// spelled with Cyrillic lookalikes is still checked against Latin lists. Text
+// with no letters at all is checked against every language.
+func guessLanguages(text string, available []string, stages Stage) []string {
+	seen := make(map[*unicode.RangeTable]bool)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if stages&StageHomoglyphs != 0 {
+			if _, ok := homoglyphs[unicode.ToLower(r)]; ok {
+				seen[unicode.Latin] = true
+			}
+		}
+		for _, language := range available {
+			for _, script := range scriptsOf(language) {
+				if !seen[script] && unicode.Is(script, r) {
+					seen[script] = true
+				}
+			}
+		}
+	}
+
+	var languages []string
+	for _, language := range available {
+		if slices.ContainsFunc(scriptsOf(language), func(script *unicode.RangeTable) bool { return seen[script] }) {
+			languages = append(languages, language)
+		}
+	}
+	if len(languages) == 0 {
+		languages = available
+	}
+
+	slices.Sort(languages)
+	return languages
+}
@@ -0,0 +1,207 @@
+package badwords
+
+import (
+	"slices"
+	"unicode"
+)
+
+// Match modes accepted by requests
+const (
+	ModeWord      = "word"      // a term only matches as a whole word or phrase
+	ModeSubstring = "substring" // a term matches anywhere, even inside other words
+)
+
+// Matcher finds every term of a word list in one pass over the text using an
+// Aho-Corasick automaton. Terms and text go through the same normalization
+// stages. It is immutable once built, so it can be shared by concurrent
+// requests and replaced wholesale when the list changes.
+type Matcher struct {
+	nodes    []node
+	patterns []pattern
+	stages   Stage
+}
+
+// node is one state of the automaton: a prefix of at least one term
+type node struct {
+	next map[rune]int32
+	fail int32
+	// Every pattern ending at this state, including those reachable through failure links
+	outputs []int32
+}
+
+// pattern is a normalized term
+type pattern struct {
+	// Index of the term in the list the matcher was built from
+	term   int
+	length int
+	// Run length of each rune after the repeats stage, so "ass" can't match "as"
+	runs []int
+}
+
+// span is a match found by the automaton, in offsets of the normalized text
+type span struct {
+	start, end int
+}
+
+// NewMatcher compiles terms into an automaton that matches them case-insensitively
+// after running them through stages
+func NewMatcher(terms []string, stages Stage) *Matcher {
+	m := &Matcher{nodes: []node{{}}, stages: stages}
+
+	for t, term := range terms {
+		units := normalize(term, stages)
+		if len(units) == 0 {
+			continue
+		}
+
+		p := pattern{term: t, length: len(units), runs: make([]int, len(units))}
+		for i, u := range units {
+			p.runs[i] = u.run
+		}
+		index := int32(len(m.patterns))
+		m.patterns = append(m.patterns, p)
+
+		state := int32(0)
+		for _, u := range units {
+			next, ok := m.nodes[state].next[u.r]
+			if !ok {
+				next = int32(len(m.nodes))
+				m.nodes = append(m.nodes, node{})
+				if m.nodes[state].next == nil {
+					m.nodes[state].next = make(map[rune]int32)
+				}
+				m.nodes[state].next[u.r] = next
+			}
+			state = next
+		}
+		m.nodes[state].outputs = append(m.nodes[state].outputs, index)
+	}
+
+	// Breadth-first, so every failure target is complete before it's used
+	queue := make([]int32, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for r, child := range m.nodes[state].next {
+			fail := m.nodes[state].fail
+			for fail != 0 && !m.hasEdge(fail, r) {
+				fail = m.nodes[fail].fail
+			}
+			if target, ok := m.nodes[fail].next[r]; ok && target != child {
+				m.nodes[child].fail = target
+			}
+
+			target := m.nodes[child].fail
+			m.nodes[child].outputs = append(m.nodes[child].outputs, m.nodes[target].outputs...)
+			queue = append(queue, child)
+		}
+	}
+
+	return m
+}
+
+func (m *Matcher) hasEdge(state int32, r rune) bool {
+	_, ok := m.nodes[state].next[r]
+	return ok
+}
+
+// step follows the automaton from state on r
+func (m *Matcher) step(state int32, r rune) int32 {
+	for {
+		if next, ok := m.nodes[state].next[r]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = m.nodes[state].fail
+	}
+}
+
+// scan walks the normalized text, calling found with every match allowed by
+// mode and the index of the term that matched. Scanning stops early if found
+// returns false.
+func (m *Matcher) scan(units []unit, mode string, found func(s span, term int) bool) {
+	state := int32(0)
+	for i, u := range units {
+		state = m.step(state, u.r)
+		for _, index := range m.nodes[state].outputs {
+			p := m.patterns[index]
+			s := span{start: i + 1 - p.length, end: i + 1}
+			if !p.coveredBy(units[s.start:s.end]) {
+				continue
+			}
+			if mode != ModeSubstring && !isWholeWord(units, s) {
+				continue
+			}
+			if !found(s, p.term) {
+				return
+			}
+		}
+	}
+}
+
+// coveredBy reports whether every run of a letter in the matched text is at
+// least as long as the same run in the pattern
+func (p pattern) coveredBy(units []unit) bool {
+	for i, u := range units {
+		if u.run < p.runs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// newMatch describes a span of normalized text in terms of the original text
+func newMatch(text string, units []unit, s span) Match {
+	first, last := units[s.start], units[s.end-1]
+	return Match{
+		Term:      text[first.start:last.end],
+		Start:     first.start,
+		End:       last.end,
+		RuneStart: first.runeStart,
+		RuneEnd:   last.runeEnd,
+	}
+}
+
+// leftmostLongest drops overlapping matches so each part of the text is
+// reported once: the leftmost match wins, and of those starting together the
+// longest. The result is sorted by position.
+func leftmostLongest(matches []Match) []Match {
+	slices.SortFunc(matches, func(a, b Match) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return b.End - a.End
+	})
+
+	kept := []Match{}
+	covered := 0
+	for _, m := range matches {
+		if m.Start < covered {
+			continue
+		}
+		covered = m.End
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// isWholeWord reports whether s is not directly preceded or followed by a letter or digit
+func isWholeWord(units []unit, s span) bool {
+	if s.start > 0 && isWordRune(units[s.start-1].r) {
+		return false
+	}
+	if s.end < len(units) && isWordRune(units[s.end].r) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,104 @@
+package badwords
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/joy095/word-filter/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMain(m *testing.M) {
+	// Every check logs; keep test runs from writing log files
+	discard := logrus.New()
+	discard.SetOutput(io.Discard)
+	logger.InfoLogger = logrus.NewEntry(discard)
+	logger.ErrorLogger = logrus.NewEntry(discard)
+	logger.AuditLogger = logrus.NewEntry(discard)
+
+	os.Exit(m.Run())
+}
+
+// Filler vocabulary for generated texts; none of it is on the list
+var filler = strings.Fields("the quick brown fox jumps over a lazy dog while classic assistants pass grass in essex")
+
+// benchmarkSizes are the text lengths, in words, each benchmark runs with
+var benchmarkSizes = []int{100, 1_000, 10_000}
+
+// generateText builds a text of n filler words with no bad words, the worst
+// case for the linear scan
+func generateText(n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = filler[i%len(filler)]
+	}
+	return strings.Join(words, " ")
+}
+
+// containsLinear is the original implementation: every word of the text is
+// compared against every entry of the list
+func containsLinear(list []string, text string) bool {
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		word = strings.Trim(word, ".,!?;:\"'()[]{}")
+		for _, badWord := range list {
+			if strings.ToLower(badWord) == word {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadDictionaries loads the shipped lists, allowlists and domain blocklist
+func loadDictionaries(tb testing.TB) {
+	tb.Helper()
+	if _, err := LoadDictionaries("."); err != nil {
+		tb.Fatalf("Failed to load dictionaries: %v", err)
+	}
+}
+
+// findEnglish returns the matches, and the matches allow rules let through,
+// of the English list with the whole pipeline enabled
+func findEnglish(t *testing.T, text string) (matches, allowed []Match) {
+	t.Helper()
+	matches, allowed, _, err := FindMatches(text, Options{Mode: ModeWord, Stages: AllStages, Languages: []string{"en"}})
+	if err != nil {
+		t.Fatalf("FindMatches(%q): %v", text, err)
+	}
+	return matches, allowed
+}
+
+// BenchmarkMatcherLinear is the baseline the compiled matcher replaced
+func BenchmarkMatcherLinear(b *testing.B) {
+	loadDictionaries(b)
+	list := ListBadWords("en")
+
+	for _, size := range benchmarkSizes {
+		text := generateText(size)
+		b.Run(fmt.Sprintf("words=%d", size), func(b *testing.B) {
+			for b.Loop() {
+				containsLinear(list, text)
+			}
+		})
+	}
+}
+
+// BenchmarkMatcherCompiled runs the full pipeline, every normalization stage
+// included, over the same texts as BenchmarkMatcherLinear
+func BenchmarkMatcherCompiled(b *testing.B) {
+	loadDictionaries(b)
+	opts := Options{Mode: ModeWord, Stages: AllStages, Languages: []string{"en"}}
+
+	for _, size := range benchmarkSizes {
+		text := generateText(size)
+		b.Run(fmt.Sprintf("words=%d", size), func(b *testing.B) {
+			for b.Loop() {
+				FindMatches(text, opts)
+			}
+		})
+	}
+}
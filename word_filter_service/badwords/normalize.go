@@ -0,0 +1,279 @@
+package badwords
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Stage is one step of the normalization pipeline run before matching
+type Stage uint8
+
+// Normalization stages, applied in this order
+const (
+	StageNFKC       Stage = 1 << iota // fold compatibility forms: fullwidth, ligatures, styled letters
+	StageHomoglyphs                   // fold lookalike letters from other scripts and strip accents
+	StageLeet                         // read digits and symbols inside words as the letters they imitate
+	StageSeparators                   // join letters spelled out one at a time, b.a.d or b a d, and drop invisible characters
+	StageRepeats                      // let a run of one letter stand for a shorter run: baaad
+)
+
+// AllStages enables the whole pipeline; it's what requests get by default
+const AllStages = StageNFKC | StageHomoglyphs | StageLeet | StageSeparators | StageRepeats
+
+// Normalization switches pipeline stages on or off for one request. Stages
+// left out stay on.
+type Normalization struct {
+	NFKC       *bool `json:"nfkc"`
+	Homoglyphs *bool `json:"homoglyphs"`
+	Leet       *bool `json:"leet"`
+	Separators *bool `json:"separators"`
+	Repeats    *bool `json:"repeats"`
+}
+
+// Stages resolves the request's switches into a set of stages
+func (n *Normalization) Stages() Stage {
+	stages := AllStages
+	if n == nil {
+		return stages
+	}
+
+	for _, toggle := range []struct {
+		enabled *bool
+		stage   Stage
+	}{
+		{n.NFKC, StageNFKC},
+		{n.Homoglyphs, StageHomoglyphs},
+		{n.Leet, StageLeet},
+		{n.Separators, StageSeparators},
+		{n.Repeats, StageRepeats},
+	} {
+		if toggle.enabled != nil && !*toggle.enabled {
+			stages &^= toggle.stage
+		}
+	}
+	return stages
+}
+
+// homoglyphs maps lowercase letters from other scripts to the Latin letters they imitate
+var homoglyphs = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'ё': 'e', 'һ': 'h', 'н': 'h',
+	'і': 'i', 'ї': 'i', 'ј': 'j', 'к': 'k', 'м': 'm', 'о': 'o', 'р': 'p', 'ԛ': 'q',
+	'ѕ': 's', 'т': 't', 'у': 'y', 'ԝ': 'w', 'х': 'x',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'γ': 'y', 'ω': 'w',
+	// Latin lookalikes
+	'ɡ': 'g', 'ı': 'i', 'ȷ': 'j', 'ʏ': 'y', 'ł': 'l', 'ø': 'o', 'đ': 'd', 'ħ': 'h',
+}
+
+// leet maps digits and symbols to the letters they stand in for
+var leet = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'l', '+': 't',
+}
+
+// unit is one rune of normalized text and the span of the original text it came from
+type unit struct {
+	r rune
+	// Byte and rune offsets of the original span, end exclusive
+	start, end         int
+	runeStart, runeEnd int
+	// How many times r repeated in a row before the repeats stage collapsed it
+	run int
+}
+
+// normalize lowercases text and runs it through the enabled stages. Every
+// resulting rune remembers which part of the original text produced it, so
+// matches can be reported against what the caller sent.
+func normalize(text string, stages Stage) []unit {
+	units := decode(text, stages&StageNFKC != 0)
+	for i := range units {
+		units[i].r = unicode.ToLower(units[i].r)
+	}
+
+	if stages&StageHomoglyphs != 0 {
+		units = foldHomoglyphs(units)
+	}
+	if stages&StageLeet != 0 {
+		foldLeet(units)
+	}
+	if stages&StageSeparators != 0 {
+		units = joinSpelledOut(dropInvisible(units))
+	}
+	if stages&StageRepeats != 0 {
+		units = collapseRepeats(units)
+	}
+	return units
+}
+
+// decode splits text into units, applying NFKC when asked. NFKC works on
+// segments of a base character and its combining marks, so every rune of a
+// segment points back at the whole segment.
+func decode(text string, nfkc bool) []unit {
+	units := make([]unit, 0, len(text))
+
+	if !nfkc {
+		runeIndex := 0
+		for i, r := range text {
+			units = append(units, unit{r: r, start: i, runeStart: runeIndex, runeEnd: runeIndex + 1, run: 1})
+			runeIndex++
+		}
+		for i := range units {
+			if i+1 < len(units) {
+				units[i].end = units[i+1].start
+			} else {
+				units[i].end = len(text)
+			}
+		}
+		return units
+	}
+
+	var it norm.Iter
+	it.InitString(norm.NFKC, text)
+	runeIndex := 0
+	for !it.Done() {
+		start := it.Pos()
+		segment := string(it.Next())
+		end := it.Pos()
+		runeEnd := runeIndex + len([]rune(text[start:end]))
+
+		for _, r := range segment {
+			units = append(units, unit{r: r, start: start, end: end, runeStart: runeIndex, runeEnd: runeEnd, run: 1})
+		}
+		runeIndex = runeEnd
+	}
+	return units
+}
+
+// foldHomoglyphs replaces lookalike letters with Latin ones and drops accents
+func foldHomoglyphs(units []unit) []unit {
+	folded := units[:0]
+	for _, u := range units {
+		if unicode.Is(unicode.Mn, u.r) {
+			// A combining accent; widen the previous rune's span over it
+			if len(folded) > 0 && folded[len(folded)-1].end <= u.start {
+				folded[len(folded)-1].end = u.end
+				folded[len(folded)-1].runeEnd = u.runeEnd
+			}
+			continue
+		}
+
+		// ASCII has nothing to fold
+		if u.r > unicode.MaxASCII {
+			if base, ok := homoglyphs[u.r]; ok {
+				u.r = base
+			} else if decomposed := []rune(norm.NFD.String(string(u.r))); len(decomposed) > 1 {
+				// Precomposed accented letter, keep its base
+				u.r = decomposed[0]
+			}
+		}
+		folded = append(folded, u)
+	}
+	return folded
+}
+
+// foldLeet reads digits and symbols as letters, but only inside words that
+// also have a letter, so "2 girls 1 cup" keeps its numbers. An exclamation
+// mark only folds when a letter or digit follows it, so "word!" stays a word
+// followed by punctuation.
+func foldLeet(units []unit) {
+	for start := 0; start < len(units); {
+		if unicode.IsSpace(units[start].r) {
+			start++
+			continue
+		}
+		end := start
+		hasLetter := false
+		for end < len(units) && !unicode.IsSpace(units[end].r) {
+			hasLetter = hasLetter || unicode.IsLetter(units[end].r)
+			end++
+		}
+
+		if hasLetter {
+			wordFollows := false
+			for i := end - 1; i >= start; i-- {
+				r := units[i].r
+				if letter, ok := leet[r]; ok && (r != '!' || wordFollows) {
+					units[i].r = letter
+				}
+				wordFollows = wordFollows || isWordRune(r)
+			}
+		}
+		start = end
+	}
+}
+
+// dropInvisible removes zero-width and other invisible characters, so a word
+// split by them reads as one. The match still covers them in the original text.
+func dropInvisible(units []unit) []unit {
+	visible := units[:0]
+	for _, u := range units {
+		if !unicode.Is(invisibleChars, u.r) {
+			visible = append(visible, u)
+		}
+	}
+	return visible
+}
+
+// joinSpelledOut drops the separators in chains of at least three single
+// letters, like b.a.d or b a d, so they read as one word. Separators between
+// longer words, as in ball-gag, are left alone.
+func joinSpelledOut(units []unit) []unit {
+	// Find maximal runs of word runes
+	type segment struct{ start, end int }
+	var segments []segment
+	for i := 0; i < len(units); {
+		if !isWordRune(units[i].r) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(units) && isWordRune(units[j].r) {
+			j++
+		}
+		segments = append(segments, segment{i, j})
+		i = j
+	}
+
+	drop := make([]bool, len(units))
+	for i := 0; i < len(segments); {
+		j := i
+		for j < len(segments) && segments[j].end-segments[j].start == 1 {
+			j++
+		}
+		if j-i >= 3 {
+			for k := i; k < j-1; k++ {
+				for g := segments[k].end; g < segments[k+1].start; g++ {
+					drop[g] = true
+				}
+			}
+		}
+		i = max(j, i+1)
+	}
+
+	joined := units[:0]
+	for i, u := range units {
+		if !drop[i] {
+			joined = append(joined, u)
+		}
+	}
+	return joined
+}
+
+// collapseRepeats squeezes runs of the same letter into one unit that
+// remembers the run length
+func collapseRepeats(units []unit) []unit {
+	collapsed := units[:0]
+	for _, u := range units {
+		if n := len(collapsed); n > 0 && isWordRune(u.r) && collapsed[n-1].r == u.r {
+			collapsed[n-1].end = u.end
+			collapsed[n-1].runeEnd = u.runeEnd
+			collapsed[n-1].run++
+			continue
+		}
+		collapsed = append(collapsed, u)
+	}
+	return collapsed
+}
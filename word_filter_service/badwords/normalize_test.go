@@ -0,0 +1,101 @@
+package badwords
+
+import "testing"
+
+func TestObfuscatedMatches(t *testing.T) {
+	loadDictionaries(t)
+
+	tests := []struct {
+		name string
+		text string
+		// The match expected, with its offsets into text
+		rule, term         string
+		start, end         int
+		runeStart, runeEnd int
+	}{
+		{"plain", "well fuck", "fuck", "fuck", 5, 9, 5, 9},
+		{"spelled out", "f.u.c.k you", "fuck", "f.u.c.k", 0, 7, 0, 7},
+		{"spelled out with spaces", "so f u c k it", "fuck", "f u c k", 3, 10, 3, 10},
+		{"repeated letters", "sooo fuuuuck", "fuck", "fuuuuck", 5, 12, 5, 12},
+		{"repeats and leet", "ok, sh!!!t", "shit", "sh!!!t", 4, 10, 4, 10},
+		{"leet exclamation mark", "oh sh!t", "shit", "sh!t", 3, 7, 3, 7},
+		{"leet at sign", "you @ss", "ass", "@ss", 4, 7, 4, 7},
+		{"leet dollar signs", "you a$$", "ass", "a$$", 4, 7, 4, 7},
+		{"leet digit", "nigg3r", "nigger", "nigg3r", 0, 6, 0, 6},
+		{"greek upsilon", "fυck", "fuck", "fυck", 0, 5, 0, 4},
+		{"cyrillic dze and i", "oh ѕhіt", "shit", "ѕhіt", 3, 9, 3, 7},
+		{"cyrillic after multi-byte text", "café ѕhіt", "shit", "ѕhіt", 6, 12, 5, 9},
+		{"fullwidth", "hey ｆｕｃｋ", "fuck", "ｆｕｃｋ", 4, 16, 4, 8},
+		{"fullwidth repeated", "ｆｕｕｕｃｋ!", "fuck", "ｆｕｕｕｃｋ", 0, 18, 0, 6},
+		{"zero-width space", "fu​ck", "fuck", "fu​ck", 0, 7, 0, 5},
+		{"zero-width joiners between every letter", "a f‍u‍c‍k", "fuck", "f‍u‍c‍k", 2, 15, 2, 9},
+		{"soft hyphen", "sh­it", "shit", "sh­it", 0, 6, 0, 5},
+		{"accents", "fúck", "fuck", "fúck", 0, 5, 0, 4},
+		{"combining accent", "fúck", "fuck", "fúck", 0, 6, 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, _ := findEnglish(t, tt.text)
+			if len(matches) != 1 {
+				t.Fatalf("FindMatches(%q) = %+v, want one match on %s", tt.text, matches, tt.rule)
+			}
+			m := matches[0]
+			if m.Rule != tt.rule || m.Term != tt.term {
+				t.Errorf("match rule %q term %q, want rule %q term %q", m.Rule, m.Term, tt.rule, tt.term)
+			}
+			if m.Start != tt.start || m.End != tt.end {
+				t.Errorf("byte offsets [%d,%d), want [%d,%d)", m.Start, m.End, tt.start, tt.end)
+			}
+			if m.RuneStart != tt.runeStart || m.RuneEnd != tt.runeEnd {
+				t.Errorf("rune offsets [%d,%d), want [%d,%d)", m.RuneStart, m.RuneEnd, tt.runeStart, tt.runeEnd)
+			}
+			if m.Term != tt.text[m.Start:m.End] {
+				t.Errorf("term %q is not the text at its byte offsets, %q", m.Term, tt.text[m.Start:m.End])
+			}
+		})
+	}
+}
+
+func TestObfuscationNeedsItsStage(t *testing.T) {
+	loadDictionaries(t)
+
+	tests := []struct {
+		text     string
+		disabled Stage
+	}{
+		{"f.u.c.k", StageSeparators},
+		{"fu​ck", StageSeparators},
+		{"fuuuuck", StageRepeats},
+		{"sh!t", StageLeet},
+		{"a$$", StageLeet},
+		{"fυck", StageHomoglyphs},
+		{"ｆｕｃｋ", StageNFKC},
+	}
+	for _, tt := range tests {
+		matches, _, _, err := FindMatches(tt.text, Options{Mode: ModeWord, Stages: AllStages &^ tt.disabled, Languages: []string{"en"}})
+		if err != nil {
+			t.Fatalf("FindMatches(%q): %v", tt.text, err)
+		}
+		if len(matches) != 0 {
+			t.Errorf("FindMatches(%q) without stage %05b = %+v, want no matches", tt.text, tt.disabled, matches)
+		}
+	}
+}
+
+func TestCleanTextIsNotFlagged(t *testing.T) {
+	loadDictionaries(t)
+
+	for _, text := range []string{
+		"room 101 has 3 beds",
+		"that's a nice word!",
+		"a b c d e f g",
+		"book keeper",
+		"price is $5",
+		// People talking about self-harm need to be heard, not masked
+		"i have been thinking about suicide",
+	} {
+		if matches, _ := findEnglish(t, text); len(matches) != 0 {
+			t.Errorf("FindMatches(%q) = %+v, want no matches", text, matches)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package badwords
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joy095/word-filter/logger"
+)
+
+// ReloadResult reports what a reload changed
+type ReloadResult struct {
+	Loaded  []string          `json:"loaded"`
+	Removed []string          `json:"removed"`
+	Failed  map[string]string `json:"failed"`
+}
+
+// Reload reads every <language>.txt in the dictionary directory again, with
+// its allowlist, and the domain blocklist, and swaps in the new lists. A file
+// that fails to read or parse is reported and keeps the list it had. A
+// language whose file is gone is dropped, as are deleted allowlists and
+// blocklists.
+func Reload() (ReloadResult, error) {
+	logger.InfoLogger.Info("Reload called")
+
+	listMu.Lock()
+	dir := dictionaryDir
+	listMu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return ReloadResult{}, err
+	}
+
+	result := ReloadResult{Loaded: []string{}, Removed: removeMissing(), Failed: map[string]string{}}
+	for _, path := range paths {
+		language := strings.TrimSuffix(filepath.Base(path), ".txt")
+		if _, err := LoadBadWords(language, path); err != nil {
+			logger.ErrorLogger.Errorf("Keeping the current %s list, failed to reload %s: %v", language, path, err)
+			result.Failed[language] = err.Error()
+			continue
+		}
+		if err := loadAllowlistFor(dir, language); err != nil {
+			logger.ErrorLogger.Errorf("Keeping the current %s allowlist: %v", language, err)
+			result.Failed[language] = err.Error()
+			continue
+		}
+		result.Loaded = append(result.Loaded, language)
+	}
+
+	if err := loadBlockedDomainsFrom(dir); err != nil {
+		logger.ErrorLogger.Errorf("Keeping the current domain blocklist: %v", err)
+		result.Failed[blocklistFile] = err.Error()
+	}
+	return result, nil
+}
+
+// removeMissing drops the languages whose list file no longer exists and
+// returns them
+func removeMissing() []string {
+	listMu.Lock()
+	defer listMu.Unlock()
+
+	removed := []string{}
+	for language, filename := range files {
+		if _, err := os.Stat(filename); !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		logger.InfoLogger.Infof("Dropping the %s list, %s was removed", language, filename)
+
+		delete(lists, language)
+		delete(allowlists, language)
+		delete(files, language)
+		next := maps.Clone(*dictionaries.Load())
+		delete(next, language)
+		dictionaries.Store(&next)
+
+		removed = append(removed, language)
+	}
+	slices.Sort(removed)
+	return removed
+}
+
+// fileStamp is what Watch compares to notice a changed file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch polls the dictionary directory every interval and reloads when a
+// file is added, removed or changed. It never returns.
+func Watch(interval time.Duration) {
+	stamps := dictionaryStamps()
+	for range time.Tick(interval) {
+		current := dictionaryStamps()
+		if maps.EqualFunc(stamps, current, fileStamp.equal) {
+			continue
+		}
+		stamps = current
+
+		logger.InfoLogger.Info("Dictionary files changed, reloading")
+		if _, err := Reload(); err != nil {
+			logger.ErrorLogger.Errorf("Failed to reload dictionaries: %v", err)
+		}
+	}
+}
+
+// dictionaryStamps returns the modification time and size of every list, allowlist and blocklist file
+func dictionaryStamps() map[string]fileStamp {
+	listMu.Lock()
+	dir := dictionaryDir
+	listMu.Unlock()
+
+	stamps := map[string]fileStamp{}
+	lists, _ := filepath.Glob(filepath.Join(dir, "*.txt"))
+	allowlists, _ := filepath.Glob(filepath.Join(dir, "*.allow"))
+	blocklists, _ := filepath.Glob(filepath.Join(dir, "*.block"))
+	for _, path := range slices.Concat(lists, allowlists, blocklists) {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps
+}
+
+func (s fileStamp) equal(other fileStamp) bool {
+	return s.modTime.Equal(other.modTime) && s.size == other.size
+}
@@ -0,0 +1,327 @@
+package badwords
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/joy095/word-filter/logger"
+)
+
+// Flags are signals about text that the dictionaries can't see: flooding,
+// shouting, contact details and links, hidden characters and lookalike words.
+// They're reported next to ContainsBadWords and don't change it; callers
+// decide which ones to act on.
+type Flags struct {
+	// A character or word repeated many times in a row
+	Repetition bool `json:"repetition"`
+	// Mostly capital letters
+	Shouting bool `json:"shouting"`
+	URL      bool `json:"url"`
+	Email    bool `json:"email"`
+	Phone    bool `json:"phone"`
+	// Domains of links and emails that are on the domain blocklist
+	BlockedDomains []string `json:"blockedDomains,omitempty"`
+	// Zero-width, bidi control or other invisible formatting characters
+	Invisible bool `json:"invisible"`
+	// A word mixing letters of several scripts, like "pаypal" with a Cyrillic "а"
+	MixedScript bool `json:"mixedScript"`
+}
+
+// Thresholds for the heuristics
+const (
+	// A run of one character at least this long is flooding
+	repeatedCharRun = 8
+	// The same word at least this many times in a row is flooding
+	repeatedWordRun = 4
+	// Text needs this many cased letters before it can count as shouting
+	shoutingMinLetters = 10
+	// Percentage of cased letters that must be capitals for shouting
+	shoutingPercent = 70
+	// A phone number has at least this many digits
+	phoneMinDigits = 9
+)
+
+var (
+	urlPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+`)
+	// Bare domains are only recognized for common TLDs, so "e.g." and "v1.2" aren't links
+	domainPattern = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+(?:com|net|org|info|biz|io|co|me|ly|gg|xyz|top|site|online|club|app|dev|link|ru|cn|tk|ml|ga|cf|gq|uk|de|in)\b`)
+	emailPattern  = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@((?:[a-z0-9-]+\.)+[a-z]{2,})\b`)
+	phonePattern  = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?\(?\b\d{2,4}\)?[\s.-]?\d{3,4}[\s.-]?\d{3,4}\b`)
+)
+
+// blockedDomains holds the domain blocklist. Readers load it without locking;
+// loading a file stores a fresh set.
+var blockedDomains atomic.Pointer[map[string]bool]
+
+func init() {
+	blockedDomains.Store(&map[string]bool{})
+}
+
+// blocklistFile is the domain blocklist's name in the dictionary directory
+const blocklistFile = "domains.block"
+
+// LoadBlockedDomains loads the domain blocklist from a text file, replacing
+// the current one. Each line holds one domain; its subdomains are blocked too.
+func LoadBlockedDomains(filename string) error {
+	logger.InfoLogger.Info("LoadBlockedDomains called")
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	// Domains are read like allowlist words: one per line, blank lines ignored
+	domains, err := parseAllowlist(data)
+	if err != nil {
+		return err
+	}
+
+	blocked := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		blocked[strings.TrimPrefix(strings.ToLower(domain), "www.")] = true
+	}
+	blockedDomains.Store(&blocked)
+
+	fmt.Printf("Loaded %d blocked domains from text file\n", len(blocked))
+	return nil
+}
+
+// loadBlockedDomainsFrom loads the domain blocklist from dir, if there is one
+func loadBlockedDomainsFrom(dir string) error {
+	filename := filepath.Join(dir, blocklistFile)
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		blockedDomains.Store(&map[string]bool{})
+		return nil
+	}
+	if err := LoadBlockedDomains(filename); err != nil {
+		return fmt.Errorf("loading %s: %w", filename, err)
+	}
+	return nil
+}
+
+// isBlocked reports whether a domain or one of its parents is on the blocklist
+func isBlocked(blocked map[string]bool, domain string) bool {
+	domain = strings.TrimPrefix(strings.ToLower(domain), "www.")
+	for domain != "" {
+		if blocked[domain] {
+			return true
+		}
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			return false
+		}
+		domain = parent
+	}
+	return false
+}
+
+// DetectFlags runs the spam and flood heuristics over text
+func DetectFlags(text string) Flags {
+	flags := Flags{
+		Repetition:  isRepetitive(text),
+		Shouting:    isShouting(text),
+		Invisible:   hasInvisible(text),
+		MixedScript: hasMixedScriptWord(text),
+	}
+
+	var domains []string
+	for _, link := range urlPattern.FindAllString(text, -1) {
+		flags.URL = true
+		if !strings.Contains(link, "://") {
+			link = "http://" + link
+		}
+		if u, err := url.Parse(link); err == nil && u.Hostname() != "" {
+			domains = append(domains, u.Hostname())
+		}
+	}
+	for _, match := range emailPattern.FindAllStringSubmatch(text, -1) {
+		flags.Email = true
+		domains = append(domains, match[1])
+	}
+	// Whatever's left that looks like a domain is a bare link
+	rest := emailPattern.ReplaceAllString(urlPattern.ReplaceAllString(text, " "), " ")
+	for _, domain := range domainPattern.FindAllString(rest, -1) {
+		flags.URL = true
+		domains = append(domains, domain)
+	}
+
+	blocked := *blockedDomains.Load()
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		if isBlocked(blocked, domain) && !slices.Contains(flags.BlockedDomains, domain) {
+			flags.BlockedDomains = append(flags.BlockedDomains, domain)
+		}
+	}
+
+	for _, loc := range phonePattern.FindAllStringIndex(rest, -1) {
+		if isPhoneNumber(rest, loc[0], loc[1]) {
+			flags.Phone = true
+			break
+		}
+	}
+	return flags
+}
+
+// isPhoneNumber reports whether the phone pattern's match at text[start:end]
+// is likely a phone number. Order numbers like #123456789 and lists of
+// years like 2019 2020 2021 have the shape of one but aren't.
+func isPhoneNumber(text string, start, end int) bool {
+	candidate := text[start:end]
+	if len(strings.Map(keepDigits, candidate)) < phoneMinDigits {
+		return false
+	}
+	if start > 0 && text[start-1] == '#' {
+		return false
+	}
+
+	groups := strings.FieldsFunc(candidate, func(r rune) bool { return keepDigits(r) == -1 })
+	return slices.ContainsFunc(groups, func(group string) bool { return !isYear(group) })
+}
+
+// isYear reports whether a group of digits reads as a year from 1900 to 2099
+func isYear(digits string) bool {
+	return len(digits) == 4 && (strings.HasPrefix(digits, "19") || strings.HasPrefix(digits, "20"))
+}
+
+func keepDigits(r rune) rune {
+	if r >= '0' && r <= '9' {
+		return r
+	}
+	return -1
+}
+
+// isRepetitive reports whether text floods with one character or one word
+func isRepetitive(text string) bool {
+	var last rune
+	run := 0
+	for _, r := range text {
+		if r == last && !unicode.IsSpace(r) {
+			run++
+		} else {
+			last, run = r, 1
+		}
+		if run >= repeatedCharRun {
+			return true
+		}
+	}
+
+	var lastWord string
+	run = 0
+	for word := range strings.FieldsSeq(strings.ToLower(text)) {
+		word = strings.TrimFunc(word, unicode.IsPunct)
+		if word != "" && word == lastWord {
+			run++
+		} else {
+			lastWord, run = word, 1
+		}
+		if run >= repeatedWordRun {
+			return true
+		}
+	}
+	return false
+}
+
+// isShouting reports whether most of the cased letters in text are capitals
+func isShouting(text string) bool {
+	upper, cased := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+			cased++
+		case unicode.IsLower(r):
+			cased++
+		}
+	}
+	return cased >= shoutingMinLetters && upper*100 >= cased*shoutingPercent
+}
+
+// invisibleChars render as nothing. They're used to split words so the
+// dictionaries miss them, to pad names that look identical, or, for the bidi
+// controls, to make text display in a different order than it's read.
+var invisibleChars = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00ad, Hi: 0x00ad, Stride: 1}, // soft hyphen
+		{Lo: 0x115f, Hi: 0x1160, Stride: 1}, // Hangul fillers
+		{Lo: 0x180e, Hi: 0x180e, Stride: 1}, // Mongolian vowel separator
+		{Lo: 0x200b, Hi: 0x200f, Stride: 1}, // zero-width space, joiners and direction marks
+		{Lo: 0x202a, Hi: 0x202e, Stride: 1}, // bidi embeddings and overrides
+		{Lo: 0x2060, Hi: 0x2064, Stride: 1}, // word joiner and invisible operators
+		{Lo: 0x2066, Hi: 0x2069, Stride: 1}, // bidi isolates
+		{Lo: 0x3164, Hi: 0x3164, Stride: 1}, // Hangul filler
+		{Lo: 0xfeff, Hi: 0xfeff, Stride: 1}, // zero-width no-break space
+		{Lo: 0xffa0, Hi: 0xffa0, Stride: 1}, // halfwidth Hangul filler
+	},
+	R32: []unicode.Range32{
+		{Lo: 0xe0000, Hi: 0xe007f, Stride: 1}, // tag characters
+	},
+}
+
+// Joiners that some scripts and emoji need
+const (
+	zeroWidthNonJoiner = '\u200c'
+	zeroWidthJoiner    = '\u200d'
+)
+
+// hasInvisible reports whether text hides invisible characters. Joiners are
+// let through where they do their job: a zero-width joiner between emoji, as
+// in family emoji, and a non-joiner after a letter of a script other than
+// Latin, as in Persian or Hindi.
+func hasInvisible(text string) bool {
+	var previous rune
+	for _, r := range text {
+		switch {
+		case r == zeroWidthJoiner && (unicode.Is(unicode.So, previous) || unicode.Is(unicode.Sk, previous) || unicode.Is(unicode.Variation_Selector, previous)):
+		case r == zeroWidthNonJoiner && unicode.IsLetter(previous) && !unicode.Is(unicode.Latin, previous):
+		case unicode.Is(invisibleChars, r):
+			return true
+		}
+		previous = r
+	}
+	return false
+}
+
+// scriptGroups are the scripts a word may use; Japanese mixes its three
+// scripts as a matter of course, so they count as one
+var scriptGroups = [][]*unicode.RangeTable{
+	{unicode.Latin},
+	{unicode.Cyrillic},
+	{unicode.Greek},
+	{unicode.Arabic},
+	{unicode.Hebrew},
+	{unicode.Han, unicode.Hiragana, unicode.Katakana},
+	{unicode.Hangul},
+	{unicode.Devanagari},
+	{unicode.Thai},
+}
+
+// hasMixedScriptWord reports whether any word in text has letters from more than one script group
+func hasMixedScriptWord(text string) bool {
+	for word := range strings.FieldsFuncSeq(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) }) {
+		group := -1
+		for _, r := range word {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			g := slices.IndexFunc(scriptGroups, func(scripts []*unicode.RangeTable) bool {
+				return unicode.IsOneOf(scripts, r)
+			})
+			if g == -1 {
+				continue
+			}
+			if group != -1 && g != group {
+				return true
+			}
+			group = g
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package badwords
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestDetectFlags(t *testing.T) {
+	loadDictionaries(t)
+
+	tests := []struct {
+		name string
+		text string
+		want Flags
+	}{
+		{"plain prose", "See you tomorrow, it was great to catch up.", Flags{}},
+
+		// Repetition
+		{"character flood", "nooooooooo", Flags{Repetition: true}},
+		{"punctuation flood", "what!!!!!!!!", Flags{Repetition: true}},
+		{"word flood", "spam spam Spam spam.", Flags{Repetition: true}},
+		{"short character run", "sooo good, hmmmmmm", Flags{}},
+		{"spaces are not a run", "a          b", Flags{}},
+		{"repeated word three times", "no no no, not again", Flags{}},
+		{"repeated words apart", "very good, very very good", Flags{}},
+
+		// Shouting
+		{"shouting", "WHY WOULD YOU DO THAT", Flags{Shouting: true}},
+		{"short capitals", "OK FINE", Flags{}},
+		{"acronyms in prose", "NASA and the FBI met at the UN", Flags{}},
+		{"mixed case", "Hello There Everyone", Flags{}},
+
+		// URL
+		{"http link", "visit https://example.org/path?q=1", Flags{URL: true}},
+		{"www link", "go to www.example.net", Flags{URL: true}},
+		{"bare domain", "see example.com for details", Flags{URL: true}},
+		{"abbreviations", "Mr. Smith et al. went, e.g. to Washington.", Flags{}},
+		{"missing space after a full stop", "the end.Next sentence", Flags{}},
+		{"version numbers", "version 1.2.3 fixed it, not v2.0", Flags{}},
+		{"decimals", "it costs 3.50 and weighs 1.25kg", Flags{}},
+		{"file names", "open main.go and notes.txt", Flags{}},
+		{"dotted words", "node.js is great", Flags{}},
+
+		// Email
+		{"email", "mail me at Bob.Smith@example.co.uk", Flags{Email: true}},
+		{"handle", "follow @someone on there", Flags{}},
+		{"at sign in prose", "meet me @ 5", Flags{}},
+
+		// Phone
+		{"dashed phone number", "call 555-123-4567", Flags{Phone: true}},
+		{"international phone number", "ring +44 20 7946 0958", Flags{Phone: true}},
+		{"bracketed area code", "my number is (555) 123 4567", Flags{Phone: true}},
+		{"dotted phone number", "555.123.4567", Flags{Phone: true}},
+		{"years", "I lived there in 1987, 2003 and 2019", Flags{}},
+		{"list of years", "between 2019 2020 2021", Flags{}},
+		{"dates", "it's on 12.05.2024 at 10.30", Flags{}},
+		{"times", "from 10:30 to 11:45", Flags{}},
+		{"order number", "order #123456789 has shipped", Flags{}},
+		{"money", "the total was 1,234,567.89", Flags{}},
+		{"short numbers", "room 101, 3 beds, 42 chairs", Flags{}},
+		{"ISBN", "ISBN 978-3-16-148410-0", Flags{}},
+
+		// Blocked domains
+		{"blocked link", "free stuff at https://bit.ly/abc", Flags{URL: true, BlockedDomains: []string{"bit.ly"}}},
+		{"blocked bare domain", "join discord.gg/abc now", Flags{URL: true, BlockedDomains: []string{"discord.gg"}}},
+		{"blocked subdomain", "see https://cdn.iplogger.org/x", Flags{URL: true, BlockedDomains: []string{"cdn.iplogger.org"}}},
+		{"blocked email domain", "write to me@onlyfans.com", Flags{Email: true, BlockedDomains: []string{"onlyfans.com"}}},
+		{"blocked domain once", "bit.ly/a and bit.ly/b", Flags{URL: true, BlockedDomains: []string{"bit.ly"}}},
+		{"domain that only ends like a blocked one", "see notbit.ly today", Flags{URL: true}},
+
+		// Invisible
+		{"zero-width space", "fu​ck", Flags{Invisible: true}},
+		{"bidi override", "abc‮dcba", Flags{Invisible: true}},
+		{"soft hyphen", "sh­it", Flags{Invisible: true}},
+		{"zero-width joiner in text", "a‍b", Flags{Invisible: true}},
+		{"emoji joiner", "👨‍👩‍👧", Flags{}},
+		{"persian non-joiner", "می‌خواهم", Flags{}},
+		{"non-joiner after latin", "a‌b", Flags{Invisible: true}},
+
+		// Mixed script
+		{"cyrillic letter in a latin word", "log in to pаypal", Flags{MixedScript: true}},
+		{"greek letter in a latin word", "free mοney", Flags{MixedScript: true}},
+		{"scripts in separate words", "hello мир καλημέρα", Flags{}},
+		{"japanese", "日本語のテキストです", Flags{}},
+		{"accents", "café naïve façade", Flags{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectFlags(tt.text)
+			if !slices.Equal(got.BlockedDomains, tt.want.BlockedDomains) {
+				t.Errorf("BlockedDomains = %v, want %v", got.BlockedDomains, tt.want.BlockedDomains)
+			}
+			got.BlockedDomains, tt.want.BlockedDomains = nil, nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectFlags(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsBlocked(t *testing.T) {
+	blocked := map[string]bool{"bit.ly": true, "example.com": true}
+
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{"bit.ly", true},
+		{"BIT.LY", true},
+		{"www.bit.ly", true},
+		{"a.b.example.com", true},
+		{"notbit.ly", false},
+		{"example.com.evil.net", false},
+		{"ly", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isBlocked(blocked, tt.domain); got != tt.want {
+			t.Errorf("isBlocked(%q) = %v, want %v", tt.domain, got, tt.want)
+		}
+	}
+}
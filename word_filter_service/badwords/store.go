@@ -0,0 +1,75 @@
+package badwords
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Term is one entry of a language's list
+type Term struct {
+	Language string `json:"language"`
+	Entry
+}
+
+// saveList writes a language's entries back to its file, one per line. The new
+// contents go to a temporary file that then replaces the old one, so readers
+// never see a half-written list. Callers must hold listMu.
+func saveList(language string, entries []Entry) error {
+	filename, ok := files[language]
+	if !ok {
+		filename = filepath.Join(dictionaryDir, language+".txt")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+language+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	var contents strings.Builder
+	for _, entry := range entries {
+		contents.WriteString(entry.String() + "\n")
+	}
+
+	if _, err := tmp.WriteString(contents.String()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return err
+	}
+
+	files[language] = filename
+	return nil
+}
+
+// SearchBadWords returns the terms containing query, case-insensitively, in
+// one language or, when language is empty, in all of them
+func SearchBadWords(language, query string) ([]Term, error) {
+	listMu.Lock()
+	defer listMu.Unlock()
+
+	languages := []string{language}
+	if language == "" {
+		languages = slices.Sorted(maps.Keys(lists))
+	} else if _, ok := lists[language]; !ok {
+		return nil, ErrUnknownLanguage
+	}
+
+	query = strings.ToLower(query)
+	terms := []Term{}
+	for _, l := range languages {
+		for _, entry := range lists[l] {
+			if strings.Contains(strings.ToLower(entry.Word), query) {
+				terms = append(terms, Term{Language: l, Entry: entry})
+			}
+		}
+	}
+	return terms, nil
+}
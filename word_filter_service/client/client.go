@@ -0,0 +1,286 @@
+// Package client is the Go SDK for word_filter_service's HTTP API. Every
+// service calls the filter through it the same way: with a timeout, retries
+// with backoff, a circuit breaker that stops calling a filter that's down, an
+// LRU cache of recent verdicts, and a policy for what to do when the filter
+// can't be reached.
+//
+//	wordFilter := client.New(client.ConfigFromEnv())
+//	response, err := wordFilter.Check(ctx, client.CheckRequest{Text: username})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors returned by the client
+var (
+	// ErrUnavailable is returned when the filter can't be reached or keeps
+	// failing, and the client fails closed
+	ErrUnavailable = errors.New("word filter service unavailable")
+	// ErrCircuitOpen is returned instead of calling a filter that has been
+	// failing; it wraps ErrUnavailable
+	ErrCircuitOpen = fmt.Errorf("%w: circuit open", ErrUnavailable)
+	// ErrRejected is returned when the filter refuses the request itself, for
+	// example for an unknown language; retrying won't help
+	ErrRejected = errors.New("word filter service rejected the request")
+)
+
+// Config controls how the client calls the filter. Zero fields get the defaults below.
+type Config struct {
+	// Where the filter's HTTP API is, such as http://word-service:8082
+	BaseURL string
+	// How long one attempt may take
+	Timeout time.Duration
+	// How many times a failed attempt is retried; negative turns retries off
+	Retries int
+	// Wait before the first retry, doubled for each retry after it, with jitter
+	Backoff time.Duration
+	// Failures in a row that open the circuit, and how long it stays open
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// How many verdicts to cache and for how long; a negative size turns caching off
+	CacheSize int
+	CacheTTL  time.Duration
+	// FailOpen lets text through unchecked when the filter is unavailable,
+	// marking the response Unchecked; otherwise calls return ErrUnavailable
+	FailOpen bool
+	// HTTPClient overrides the client used for requests; Timeout still applies per attempt
+	HTTPClient *http.Client
+}
+
+// Defaults for Config fields left at zero
+const (
+	DefaultTimeout          = 3 * time.Second
+	DefaultRetries          = 2
+	DefaultBackoff          = 100 * time.Millisecond
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+	DefaultCacheSize        = 1024
+	DefaultCacheTTL         = 5 * time.Minute
+)
+
+// maxBackoff caps the wait between retries
+const maxBackoff = 2 * time.Second
+
+// ConfigFromEnv reads the configuration from the environment:
+// WORD_FILTER_SERVICE_URL, WORD_FILTER_TIMEOUT, WORD_FILTER_RETRIES,
+// WORD_FILTER_CACHE_SIZE and WORD_FILTER_FAIL_OPEN. Unset or invalid values
+// get the defaults.
+func ConfigFromEnv() Config {
+	config := Config{BaseURL: os.Getenv("WORD_FILTER_SERVICE_URL")}
+	if timeout, err := time.ParseDuration(os.Getenv("WORD_FILTER_TIMEOUT")); err == nil {
+		config.Timeout = timeout
+	}
+	if retries, err := strconv.Atoi(os.Getenv("WORD_FILTER_RETRIES")); err == nil {
+		config.Retries = retries
+	}
+	if size, err := strconv.Atoi(os.Getenv("WORD_FILTER_CACHE_SIZE")); err == nil {
+		config.CacheSize = size
+	}
+	if failOpen, err := strconv.ParseBool(os.Getenv("WORD_FILTER_FAIL_OPEN")); err == nil {
+		config.FailOpen = failOpen
+	}
+	return config
+}
+
+// Client calls word_filter_service. It's safe for concurrent use and meant to
+// be created once and shared.
+type Client struct {
+	config  Config
+	http    *http.Client
+	breaker *breaker
+	cache   *cache
+}
+
+// New creates a Client, filling in defaults for the fields config leaves at zero
+func New(config Config) *Client {
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Retries == 0 {
+		config.Retries = DefaultRetries
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = DefaultBackoff
+	}
+	if config.BreakerThreshold == 0 {
+		config.BreakerThreshold = DefaultBreakerThreshold
+	}
+	if config.BreakerCooldown <= 0 {
+		config.BreakerCooldown = DefaultBreakerCooldown
+	}
+	if config.CacheSize == 0 {
+		config.CacheSize = DefaultCacheSize
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultCacheTTL
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	return &Client{
+		config:  config,
+		http:    httpClient,
+		breaker: newBreaker(config.BreakerThreshold, config.BreakerCooldown),
+		cache:   newCache(config.CacheSize, config.CacheTTL),
+	}
+}
+
+// Check asks whether text contains bad words
+func (c *Client) Check(ctx context.Context, req CheckRequest) (*CheckResponse, error) {
+	var response CheckResponse
+	err := c.call(ctx, "/check", req, &response)
+	if c.failOpen(err) {
+		return &CheckResponse{Unchecked: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Censor asks for text with its bad words masked
+func (c *Client) Censor(ctx context.Context, req CensorRequest) (*CensorResponse, error) {
+	var response CensorResponse
+	err := c.call(ctx, "/censor", req, &response)
+	if c.failOpen(err) {
+		return &CensorResponse{Censored: req.Text, Unchecked: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CheckBatch checks several keyed texts in one request
+func (c *Client) CheckBatch(ctx context.Context, items []BatchItem) (*BatchResponse, error) {
+	var response BatchResponse
+	err := c.call(ctx, "/check/batch", map[string][]BatchItem{"items": items}, &response)
+	if c.failOpen(err) {
+		return &BatchResponse{Results: map[string]BatchResult{}, Unchecked: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// failOpen reports whether err should be swallowed under the fail-open policy
+func (c *Client) failOpen(err error) bool {
+	return err != nil && c.config.FailOpen && errors.Is(err, ErrUnavailable)
+}
+
+// call posts req to path and decodes the response into out, going through
+// the cache, the circuit breaker and retries
+func (c *Client) call(ctx context.Context, path string, req, out any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	key := path + "\x00" + string(body)
+	if cached, ok := c.cache.get(key); ok {
+		return json.Unmarshal(cached, out)
+	}
+
+	if c.config.BaseURL == "" {
+		return fmt.Errorf("%w: WORD_FILTER_SERVICE_URL is not set", ErrUnavailable)
+	}
+	// A URL that can't make a request won't get better with retries, and says
+	// nothing about the filter's health
+	if _, err := http.NewRequest(http.MethodPost, c.config.BaseURL+path, nil); err != nil {
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	if !c.breaker.allow() {
+		return ErrCircuitOpen
+	}
+
+	for attempt := 0; ; attempt++ {
+		var data []byte
+		data, err = c.post(ctx, path, body)
+		if err == nil {
+			c.breaker.success()
+			c.cache.put(key, data)
+			return json.Unmarshal(data, out)
+		}
+		if errors.Is(err, ErrRejected) {
+			// The filter answered, so it's up
+			c.breaker.success()
+			return err
+		}
+		if attempt >= c.config.Retries || ctx.Err() != nil {
+			break
+		}
+
+		wait := min(c.config.Backoff<<attempt, maxBackoff)
+		wait = wait/2 + rand.N(wait/2+1)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+		}
+	}
+
+	if ctx.Err() != nil {
+		// The caller gave up, which says nothing about the filter. Its own
+		// error comes back as is, so fail-open doesn't answer a request
+		// nobody is waiting on.
+		c.breaker.abandon()
+		return ctx.Err()
+	}
+	c.breaker.failure()
+	return err
+}
+
+// post makes one attempt at a request. Errors wrap ErrUnavailable if the
+// attempt could be retried and ErrRejected if it couldn't.
+func (c *Client) post(ctx context.Context, path string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	switch {
+	case response.StatusCode == http.StatusOK:
+		return data, nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return nil, fmt.Errorf("%w: unexpected status %d", ErrUnavailable, response.StatusCode)
+	default:
+		var failure struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &failure) == nil && failure.Error != "" {
+			return nil, fmt.Errorf("%w: %s", ErrRejected, failure.Error)
+		}
+		return nil, fmt.Errorf("%w: status %d", ErrRejected, response.StatusCode)
+	}
+}
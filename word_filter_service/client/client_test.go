@@ -0,0 +1,331 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joy095/word-filter/client"
+	"github.com/joy095/word-filter/client/fake"
+)
+
+// newClient returns a client of filter that doesn't wait between retries,
+// with config's non-zero fields taking precedence
+func newClient(filter *fake.Server, config client.Config) *client.Client {
+	config.BaseURL = filter.URL
+	if config.Backoff == 0 {
+		config.Backoff = time.Millisecond
+	}
+	return client.New(config)
+}
+
+func check(c *client.Client, text string) (*client.CheckResponse, error) {
+	return c.Check(context.Background(), client.CheckRequest{Text: text})
+}
+
+func TestCheckFindsBadWords(t *testing.T) {
+	filter := fake.NewServer("badword")
+	defer filter.Close()
+	c := newClient(filter, client.Config{})
+
+	response, err := check(c, "a BadWord here")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if !response.ContainsBadWords || response.Match == nil || response.Match.Rule != "badword" {
+		t.Errorf("Check = %+v, want a match on badword", response)
+	}
+}
+
+func TestRetriesThenGivesUp(t *testing.T) {
+	filter := fake.NewServer()
+	defer filter.Close()
+	filter.FailWith(http.StatusServiceUnavailable)
+	c := newClient(filter, client.Config{Retries: 2, BreakerThreshold: -1, CacheSize: -1})
+
+	if _, err := check(c, "hello"); !errors.Is(err, client.ErrUnavailable) {
+		t.Fatalf("Check error = %v, want ErrUnavailable", err)
+	}
+	if calls := filter.Calls(); calls != 3 {
+		t.Errorf("filter got %d calls, want 3: one attempt and two retries", calls)
+	}
+}
+
+func TestRetriesRecover(t *testing.T) {
+	filter := fake.NewServer()
+	defer filter.Close()
+	filter.FailWith(http.StatusServiceUnavailable)
+	c := newClient(filter, client.Config{Retries: 5, Backoff: 20 * time.Millisecond, BreakerThreshold: -1})
+
+	time.AfterFunc(30*time.Millisecond, func() { filter.FailWith(0) })
+	if _, err := check(c, "hello"); err != nil {
+		t.Fatalf("Check didn't recover once the filter came back: %v", err)
+	}
+}
+
+func TestRejectedIsNotRetried(t *testing.T) {
+	filter := fake.NewServer()
+	defer filter.Close()
+	filter.FailWith(http.StatusBadRequest)
+	c := newClient(filter, client.Config{Retries: 3, BreakerThreshold: 1, CacheSize: -1})
+
+	for range 3 {
+		if _, err := check(c, "hello"); !errors.Is(err, client.ErrRejected) {
+			t.Fatalf("Check error = %v, want ErrRejected", err)
+		}
+	}
+	// One call each: no retries, and the circuit never opened
+	if calls := filter.Calls(); calls != 3 {
+		t.Errorf("filter got %d calls, want 3", calls)
+	}
+}
+
+func TestCircuitOpensAfterThreshold(t *testing.T) {
+	filter := fake.NewServer()
+	defer filter.Close()
+	filter.FailWith(http.StatusServiceUnavailable)
+	c := newClient(filter, client.Config{Retries: -1, BreakerThreshold: 3, BreakerCooldown: time.Hour, CacheSize: -1})
+
+	for range 3 {
+		if _, err := check(c, "hello"); !errors.Is(err, client.ErrUnavailable) || errors.Is(err, client.ErrCircuitOpen) {
+			t.Fatalf("Check error = %v, want ErrUnavailable from the filter", err)
+		}
+	}
+
+	_, err := check(c, "hello")
+	if !errors.Is(err, client.ErrCircuitOpen) || !errors.Is(err, client.ErrUnavailable) {
+		t.Fatalf("Check error = %v, want ErrCircuitOpen wrapping ErrUnavailable", err)
+	}
+	if calls := filter.Calls(); calls != 3 {
+		t.Errorf("filter got %d calls, want 3: the open circuit should turn the last one away", calls)
+	}
+}
+
+// gate holds requests back while hold is set, until release is closed, to
+// keep a probe in flight
+type gate struct {
+	hold    atomic.Bool
+	release chan struct{}
+}
+
+func (g *gate) RoundTrip(r *http.Request) (*http.Response, error) {
+	if g.hold.Load() {
+		<-g.release
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestHalfOpenLetsOneProbeThrough(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+
+	filter := fake.NewServer()
+	defer filter.Close()
+	filter.FailWith(http.StatusServiceUnavailable)
+	g := &gate{release: make(chan struct{})}
+	c := newClient(filter, client.Config{
+		Retries:          -1,
+		BreakerThreshold: 1,
+		BreakerCooldown:  cooldown,
+		CacheSize:        -1,
+		HTTPClient:       &http.Client{Transport: g},
+	})
+
+	check(c, "hello")
+	if calls := filter.Calls(); calls != 1 {
+		t.Fatalf("filter got %d calls, want 1", calls)
+	}
+
+	// Once the cooldown is over, one probe goes out while the rest are turned away
+	time.Sleep(2 * cooldown)
+	filter.FailWith(0)
+	g.hold.Store(true)
+
+	const callers = 5
+	errs := make(chan error, callers)
+	for range callers {
+		go func() {
+			_, err := check(c, "hello")
+			errs <- err
+		}()
+	}
+
+	// Every caller but the probe returns straight away
+	for range callers - 1 {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, client.ErrCircuitOpen) {
+				t.Fatalf("Check error while half open = %v, want ErrCircuitOpen", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("callers were kept waiting behind the probe")
+		}
+	}
+
+	close(g.release)
+	if err := <-errs; err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if calls := filter.Calls(); calls != 2 {
+		t.Errorf("filter got %d calls, want 2: the first failure and one probe", calls)
+	}
+
+	// The probe succeeded, so the circuit is closed again
+	if _, err := check(c, "hello again"); err != nil {
+		t.Errorf("Check after a successful probe: %v", err)
+	}
+}
+
+func TestCacheSkipsServer(t *testing.T) {
+	filter := fake.NewServer("badword")
+	defer filter.Close()
+	c := newClient(filter, client.Config{CacheSize: 2})
+
+	first, err := check(c, "badword")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	second, err := check(c, "badword")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if calls := filter.Calls(); calls != 1 {
+		t.Errorf("filter got %d calls, want 1: the second check should be cached", calls)
+	}
+	if first.ContainsBadWords != second.ContainsBadWords || second.Match == nil {
+		t.Errorf("cached response %+v differs from %+v", second, first)
+	}
+
+	// Censor requests are cached apart from checks of the same text
+	c.Censor(context.Background(), client.CensorRequest{Text: "badword"})
+	if calls := filter.Calls(); calls != 2 {
+		t.Errorf("filter got %d calls, want 2", calls)
+	}
+
+	// Two more texts push the first check out of the cache
+	check(c, "one")
+	check(c, "two")
+	check(c, "badword")
+	if calls := filter.Calls(); calls != 5 {
+		t.Errorf("filter got %d calls, want 5: the least recently used entry should be evicted", calls)
+	}
+}
+
+func TestCacheEntriesExpire(t *testing.T) {
+	const ttl = 50 * time.Millisecond
+
+	filter := fake.NewServer("badword")
+	defer filter.Close()
+	c := newClient(filter, client.Config{CacheTTL: ttl})
+
+	check(c, "badword")
+	// A change to the list shows up once the cached verdict expires
+	filter.SetWords()
+	time.Sleep(2 * ttl)
+
+	response, err := check(c, "badword")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if response.ContainsBadWords {
+		t.Error("Check returned the expired cached verdict")
+	}
+	if calls := filter.Calls(); calls != 2 {
+		t.Errorf("filter got %d calls, want 2", calls)
+	}
+}
+
+func TestFailOpen(t *testing.T) {
+	filter := fake.NewServer("badword")
+	defer filter.Close()
+	filter.FailWith(http.StatusServiceUnavailable)
+	c := newClient(filter, client.Config{Retries: -1, FailOpen: true})
+
+	response, err := check(c, "badword")
+	if err != nil {
+		t.Fatalf("Check error = %v, want the text let through", err)
+	}
+	if !response.Unchecked || response.ContainsBadWords {
+		t.Errorf("Check = %+v, want Unchecked and no bad words", response)
+	}
+
+	censored, err := c.Censor(context.Background(), client.CensorRequest{Text: "badword"})
+	if err != nil {
+		t.Fatalf("Censor error = %v, want the text let through", err)
+	}
+	if !censored.Unchecked || censored.Censored != "badword" {
+		t.Errorf("Censor = %+v, want Unchecked and the original text", censored)
+	}
+
+	// Failing open doesn't cover requests the filter rejects
+	filter.FailWith(http.StatusBadRequest)
+	if _, err := check(c, "other"); !errors.Is(err, client.ErrRejected) {
+		t.Errorf("Check error = %v, want ErrRejected", err)
+	}
+}
+
+func TestFailClosed(t *testing.T) {
+	filter := fake.NewServer()
+	defer filter.Close()
+	filter.FailWith(http.StatusServiceUnavailable)
+	c := newClient(filter, client.Config{Retries: -1})
+
+	if _, err := check(c, "hello"); !errors.Is(err, client.ErrUnavailable) {
+		t.Errorf("Check error = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestCanceledCallIsNotFailedOpen(t *testing.T) {
+	filter := fake.NewServer("badword")
+	defer filter.Close()
+	filter.FailWith(http.StatusServiceUnavailable)
+	c := newClient(filter, client.Config{
+		Retries:          5,
+		Backoff:          time.Hour,
+		BreakerThreshold: 1,
+		BreakerCooldown:  time.Hour,
+		CacheSize:        -1,
+		FailOpen:         true,
+	})
+
+	// The caller gives up while the client waits to retry
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	response, err := c.Check(ctx, client.CheckRequest{Text: "badword"})
+	if !errors.Is(err, context.Canceled) || errors.Is(err, client.ErrUnavailable) {
+		t.Fatalf("Check = %+v, %v; want context.Canceled and no verdict", response, err)
+	}
+	if _, err := c.Censor(ctx, client.CensorRequest{Text: "badword"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Censor with a canceled context error = %v, want context.Canceled", err)
+	}
+
+	// Giving up said nothing about the filter, so the circuit is still closed
+	filter.FailWith(0)
+	if _, err := check(c, "badword"); err != nil {
+		t.Errorf("Check after a canceled call: %v", err)
+	}
+}
+
+func TestInvalidURLIsRejected(t *testing.T) {
+	c := client.New(client.Config{
+		BaseURL:          "http://bad host",
+		Retries:          3,
+		Backoff:          time.Millisecond,
+		BreakerThreshold: 1,
+		FailOpen:         true,
+	})
+
+	for range 2 {
+		_, err := check(c, "hello")
+		if !errors.Is(err, client.ErrRejected) {
+			t.Fatalf("Check error = %v, want ErrRejected", err)
+		}
+		// Neither failed open nor counted against the filter
+		if errors.Is(err, client.ErrUnavailable) {
+			t.Fatalf("Check error = %v wraps ErrUnavailable", err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Package fake is a stand-in word_filter_service for tests of code that uses
+// the client package. It serves /check, /censor and /check/batch from an
+// httptest server, flagging a fixed list of words, and can be told to fail.
+//
+//	filter := fake.NewServer("badword")
+//	defer filter.Close()
+//	wordFilter := client.New(client.Config{BaseURL: filter.URL})
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/joy095/word-filter/client"
+)
+
+// Server is a fake word filter. Words are matched case-insensitively as
+// substrings, with none of the real filter's normalization.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	words  []string
+	status int
+
+	calls atomic.Int64
+}
+
+// NewServer starts a fake filter that flags the given words
+func NewServer(words ...string) *Server {
+	s := &Server{}
+	s.SetWords(words...)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /check", s.handleCheck)
+	mux.HandleFunc("POST /censor", s.handleCensor)
+	mux.HandleFunc("POST /check/batch", s.handleBatch)
+	s.Server = httptest.NewServer(s.counting(mux))
+	return s
+}
+
+// SetWords replaces the words the fake flags
+func (s *Server) SetWords(words ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.words = s.words[:0]
+	for _, word := range words {
+		s.words = append(s.words, strings.ToLower(word))
+	}
+}
+
+// FailWith makes every request answer with status, such as 503, until it's
+// called again with 0
+func (s *Server) FailWith(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = status
+}
+
+// Calls returns how many requests the fake has received
+func (s *Server) Calls() int {
+	return int(s.calls.Load())
+}
+
+func (s *Server) counting(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls.Add(1)
+
+		s.mu.Lock()
+		status := s.status
+		s.mu.Unlock()
+		if status != 0 {
+			writeJSON(w, status, map[string]string{"error": http.StatusText(status)})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	var req client.CheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Text == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "text is required"})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.check(req.Text))
+}
+
+func (s *Server) handleCensor(w http.ResponseWriter, r *http.Request) {
+	var req client.CensorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Text == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "text is required"})
+		return
+	}
+
+	matches := s.find(req.Text)
+	censored := req.Text
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		censored = censored[:m.Start] + strings.Repeat("*", m.End-m.Start) + censored[m.End:]
+	}
+	writeJSON(w, http.StatusOK, client.CensorResponse{
+		ContainsBadWords: len(matches) > 0,
+		Languages:        []string{"en"},
+		Matches:          matches,
+		Allowed:          []client.Match{},
+		Censored:         censored,
+	})
+}
+
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Items []client.BatchItem `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Items) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "items are required"})
+		return
+	}
+
+	response := client.BatchResponse{Results: map[string]client.BatchResult{}}
+	for _, item := range req.Items {
+		result := s.check(item.Text)
+		response.Results[item.Key] = client.BatchResult{Result: &result}
+		response.ContainsBadWords = response.ContainsBadWords || result.ContainsBadWords
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) check(text string) client.CheckResponse {
+	response := client.CheckResponse{Languages: []string{"en"}, Categories: map[string]client.CategoryHits{}}
+	for i, m := range s.find(text) {
+		if i == 0 {
+			response.Match = &m
+		}
+		response.ContainsBadWords = true
+		response.Score += m.Severity
+		hits := response.Categories[m.Category]
+		hits.Hits++
+		hits.MaxSeverity = max(hits.MaxSeverity, m.Severity)
+		response.Categories[m.Category] = hits
+	}
+	return response
+}
+
+// find returns every occurrence of the fake's words in text, in order. Words
+// are treated as profanity of severity 2, the real filter's default.
+func (s *Server) find(text string) []client.Match {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lower := strings.ToLower(text)
+	matches := []client.Match{}
+	for start := 0; start < len(lower); start++ {
+		for _, word := range s.words {
+			if word != "" && strings.HasPrefix(lower[start:], word) {
+				end := start + len(word)
+				matches = append(matches, client.Match{
+					Term:     text[start:end],
+					Start:    start,
+					End:      end,
+					Language: "en",
+					Rule:     word,
+					Category: "profanity",
+					Severity: 2,
+				})
+				start = end - 1
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,109 @@
+package client
+
+// Normalization switches the filter's normalization stages on or off. Stages
+// left nil stay on.
+type Normalization struct {
+	NFKC       *bool `json:"nfkc,omitempty"`
+	Homoglyphs *bool `json:"homoglyphs,omitempty"`
+	Leet       *bool `json:"leet,omitempty"`
+	Separators *bool `json:"separators,omitempty"`
+	Repeats    *bool `json:"repeats,omitempty"`
+}
+
+// CheckRequest asks whether text contains bad words. Everything but Text is optional.
+type CheckRequest struct {
+	Text          string         `json:"text"`
+	Mode          string         `json:"mode,omitempty"`
+	Normalization *Normalization `json:"normalization,omitempty"`
+	Languages     []string       `json:"languages,omitempty"`
+	MinSeverity   int            `json:"minSeverity,omitempty"`
+	Categories    []string       `json:"categories,omitempty"`
+}
+
+// Match is one bad word found in the text
+type Match struct {
+	Term      string `json:"term"`
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	RuneStart int    `json:"runeStart"`
+	RuneEnd   int    `json:"runeEnd"`
+	Language  string `json:"language"`
+	Rule      string `json:"rule"`
+	Category  string `json:"category"`
+	Severity  int    `json:"severity"`
+	AllowedBy string `json:"allowedBy,omitempty"`
+	AllowRule string `json:"allowRule,omitempty"`
+}
+
+// CategoryHits summarizes the matches in one category
+type CategoryHits struct {
+	Hits        int `json:"hits"`
+	MaxSeverity int `json:"maxSeverity"`
+}
+
+// Flags are the filter's spam heuristics
+type Flags struct {
+	Repetition     bool     `json:"repetition"`
+	Shouting       bool     `json:"shouting"`
+	URL            bool     `json:"url"`
+	Email          bool     `json:"email"`
+	Phone          bool     `json:"phone"`
+	BlockedDomains []string `json:"blockedDomains,omitempty"`
+	Invisible      bool     `json:"invisible"`
+	MixedScript    bool     `json:"mixedScript"`
+}
+
+// CheckResponse is the filter's verdict on a text. Unchecked is set instead
+// when the filter couldn't be reached and the client fails open.
+type CheckResponse struct {
+	ContainsBadWords bool                    `json:"containsBadWords"`
+	Languages        []string                `json:"languages"`
+	Match            *Match                  `json:"match,omitempty"`
+	Categories       map[string]CategoryHits `json:"categories"`
+	Score            int                     `json:"score"`
+	Flags            Flags                   `json:"flags"`
+	Unchecked        bool                    `json:"-"`
+}
+
+// CensorRequest asks for text with its bad words masked
+type CensorRequest struct {
+	Text          string         `json:"text"`
+	Mode          string         `json:"mode,omitempty"`
+	Normalization *Normalization `json:"normalization,omitempty"`
+	Languages     []string       `json:"languages,omitempty"`
+	MinSeverity   int            `json:"minSeverity,omitempty"`
+	Categories    []string       `json:"categories,omitempty"`
+	MaskStyle     string         `json:"maskStyle,omitempty"`
+	Replacement   string         `json:"replacement,omitempty"`
+}
+
+// CensorResponse is the masked text along with what was masked. When the
+// client fails open, Censored is the original text and Unchecked is set.
+type CensorResponse struct {
+	ContainsBadWords bool     `json:"containsBadWords"`
+	Languages        []string `json:"languages"`
+	Matches          []Match  `json:"matches"`
+	Allowed          []Match  `json:"allowed"`
+	Censored         string   `json:"censored"`
+	Unchecked        bool     `json:"-"`
+}
+
+// BatchItem is one keyed text of a batch
+type BatchItem struct {
+	Key string `json:"key"`
+	CheckRequest
+}
+
+// BatchResult is the outcome of one item: the check's response, or why the
+// item couldn't be checked
+type BatchResult struct {
+	Result *CheckResponse `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// BatchResponse holds the result for each key
+type BatchResponse struct {
+	ContainsBadWords bool                   `json:"containsBadWords"`
+	Results          map[string]BatchResult `json:"results"`
+	Unchecked        bool                   `json:"-"`
+}
@@ -1,14 +1,22 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/joy095/word-filter/badwords"
 	"github.com/joy095/word-filter/config"
+	"github.com/joy095/word-filter/controllers"
+	"github.com/joy095/word-filter/grpcserver"
 	"github.com/joy095/word-filter/logger"
+	"github.com/joy095/word-filter/metrics"
+	"github.com/joy095/word-filter/middlewares/auth"
+	"github.com/joy095/word-filter/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,16 +28,33 @@ func init() {
 }
 
 func main() {
-	// Set up Gin router
+	// Set up Gin router, counting and timing every request
 	router := gin.Default()
+	router.Use(metrics.GinMiddleware())
 
-	// Step 1: Load bad words from a text file
-	badwords.LoadBadWords("badwords/en.txt")
+	// Step 1: Load a bad word dictionary per language from <lang>.txt files
+	dictionaryDir := os.Getenv("BADWORDS_DIR")
+	if dictionaryDir == "" {
+		dictionaryDir = "badwords"
+	}
+
+	// Serving without a word list would let everything through
+	languages, err := badwords.LoadDictionaries(dictionaryDir)
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to load bad words: %v", err)
+		log.Fatalf("Failed to load bad words: %v", err)
+	}
 
-	logger.InfoLogger.Info("Bad words loaded successfully!")
+	logger.InfoLogger.Infof("Bad words loaded successfully for %v", languages)
 
 	fmt.Println("Bad words loaded successfully!")
 
+	// Step 2: Pick up edited dictionary files without a restart, on SIGHUP or by polling
+	go reloadOnSignal()
+	if interval := utils.GetWatchInterval(); interval > 0 {
+		go badwords.Watch(interval)
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8082"
@@ -49,15 +74,124 @@ func main() {
 		}
 
 		// Use the updated CheckText function to check for bad words
-		response := badwords.CheckText(request.Text)
+		response, err := badwords.CheckText(request)
+		if errors.Is(err, badwords.ErrUnknownLanguage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.ErrorLogger.Error(err.Error())
+
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check text"})
+			return
+		}
 		c.JSON(http.StatusOK, response)
 	})
 
+	// Check several keyed texts, such as the fields of a profile, in one round trip
+	router.POST("/check/batch", func(c *gin.Context) {
+		logger.InfoLogger.Info("Batch check route hit")
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, badwords.MaxBatchBodyBytes)
+
+		var request badwords.BatchRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			logger.ErrorLogger.Error(err.Error())
+
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		response, err := badwords.CheckBatch(request)
+		if errors.Is(err, badwords.ErrBatchTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, badwords.ErrDuplicateKey) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.ErrorLogger.Error(err.Error())
+
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check texts"})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Report which bad words matched, where, and a masked copy of the text
+	router.POST("/censor", func(c *gin.Context) {
+		logger.InfoLogger.Info("Censor route hit")
+
+		var request badwords.CensorRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			logger.ErrorLogger.Error(err.Error())
+
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		response, err := badwords.CensorText(request)
+		if errors.Is(err, badwords.ErrUnknownLanguage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.ErrorLogger.Error(err.Error())
+
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to censor text"})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Word list management, restricted to admins. Without the secret admin
+	// tokens are signed with, no token can be trusted, so the routes stay off.
+	if utils.GetJWTSecret() != nil {
+		adminController := controllers.NewAdminController()
+		admin := router.Group("/admin", auth.AdminMiddleware())
+		{
+			admin.GET("/words", adminController.ListWords)
+			admin.POST("/words", adminController.AddWord)
+			admin.DELETE("/words", adminController.RemoveWord)
+			admin.POST("/reload", adminController.Reload)
+			admin.GET("/terms/top", adminController.TopTerms)
+		}
+	} else {
+		logger.ErrorLogger.Error("JWT_SECRET is not set, admin endpoints are disabled")
+		fmt.Println("WARNING: JWT_SECRET environment variable not set, admin endpoints are disabled.")
+	}
+
 	// Health check endpoint (keeping this as it's a good practice)
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "ok"})
 	})
 
+	// Request counts, latencies and term hits in the Prometheus text format
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Serve the same checks over gRPC for services that import the generated client
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9082"
+	}
+	go func() {
+		grpcAddr := ":" + grpcPort
+		logger.InfoLogger.Info("Starting gRPC server on " + grpcAddr)
+		if err := grpcserver.Serve(grpcAddr); err != nil {
+			logger.ErrorLogger.Errorf("Failed to start gRPC server: %v", err)
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	// Start the Gin server directly
 	serverAddr := ":" + port
 	logger.InfoLogger.Info("Starting HTTP server on " + serverAddr)
@@ -68,3 +202,16 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// reloadOnSignal reloads the dictionaries every time the process receives SIGHUP
+func reloadOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		logger.InfoLogger.Info("SIGHUP received, reloading dictionaries")
+		if _, err := badwords.Reload(); err != nil {
+			logger.ErrorLogger.Errorf("Failed to reload dictionaries: %v", err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/joy095/word-filter/badwords"
+	"github.com/joy095/word-filter/logger"
+	"github.com/joy095/word-filter/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminController handles requests that manage the word lists
+type AdminController struct{}
+
+// NewAdminController creates a new AdminController
+func NewAdminController() *AdminController {
+	return &AdminController{}
+}
+
+// wordRequest names one term of one language's list
+type wordRequest struct {
+	Language string `json:"language" form:"language" binding:"required"`
+	Word     string `json:"word" form:"word" binding:"required,max=100"`
+}
+
+// addWordRequest is a new term with its category, severity and the words it
+// shouldn't be flagged inside. Category and severity default to those of a plain list line.
+type addWordRequest struct {
+	wordRequest
+	Category   string   `json:"category" binding:"omitempty,oneof=profanity slur sexual violence spam"`
+	Severity   int      `json:"severity" binding:"omitempty,min=1,max=3"`
+	Exceptions []string `json:"exceptions" binding:"omitempty,dive,required,max=100"`
+}
+
+// topTermsRequest asks for the limit terms hit most over the last window,
+// such as 1h or 30m
+type topTermsRequest struct {
+	Window string `form:"window"`
+	Limit  int    `form:"limit" binding:"min=1,max=100"`
+}
+
+// audit records who changed which list
+func audit(c *gin.Context, action string, req wordRequest, exceptions []string) {
+	logger.AuditLogger.WithFields(map[string]any{
+		"user_id":    c.GetString("user_id"),
+		"action":     action,
+		"language":   req.Language,
+		"word":       req.Word,
+		"exceptions": exceptions,
+		"ip":         c.ClientIP(),
+	}).Info("Word list changed")
+}
+
+// ListWords lists the terms of one language or all of them, optionally
+// filtered to those containing q
+func (ac *AdminController) ListWords(c *gin.Context) {
+	logger.InfoLogger.Info("ListWords handler called")
+
+	terms, err := badwords.SearchBadWords(c.Query("language"), c.Query("q"))
+	if errors.Is(err, badwords.ErrUnknownLanguage) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown language"})
+		return
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to search words: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search words"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"languages": badwords.Languages(),
+		"words":     terms,
+	})
+}
+
+// AddWord adds a term to a language's list and saves the list
+func (ac *AdminController) AddWord(c *gin.Context) {
+	logger.InfoLogger.Info("AddWord handler called")
+
+	var req addWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := badwords.AddBadWord(req.Language, badwords.Entry{
+		Word:       req.Word,
+		Category:   req.Category,
+		Severity:   req.Severity,
+		Exceptions: req.Exceptions,
+	})
+	switch {
+	case errors.Is(err, badwords.ErrInvalidLanguage), errors.Is(err, badwords.ErrInvalidWord), errors.Is(err, badwords.ErrInvalidCategory):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, badwords.ErrDuplicateWord):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		logger.ErrorLogger.Errorf("Failed to add word: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add word"})
+		return
+	}
+
+	audit(c, "add", req.wordRequest, req.Exceptions)
+	c.JSON(http.StatusCreated, gin.H{"message": "Word added"})
+}
+
+// RemoveWord removes a term from a language's list and saves the list
+func (ac *AdminController) RemoveWord(c *gin.Context) {
+	logger.InfoLogger.Info("RemoveWord handler called")
+
+	var req wordRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := badwords.RemoveBadWord(req.Language, req.Word)
+	if errors.Is(err, badwords.ErrWordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, badwords.ErrLastWord) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to remove word: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove word"})
+		return
+	}
+
+	audit(c, "remove", req, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Word removed"})
+}
+
+// Reload reads the dictionary files again, keeping the current list for any file that fails
+func (ac *AdminController) Reload(c *gin.Context) {
+	logger.InfoLogger.Info("Reload handler called")
+
+	result, err := badwords.Reload()
+	if err != nil {
+		logger.ErrorLogger.Errorf("Failed to reload dictionaries: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload dictionaries"})
+		return
+	}
+
+	logger.AuditLogger.WithFields(map[string]any{
+		"user_id": c.GetString("user_id"),
+		"action":  "reload",
+		"loaded":  result.Loaded,
+		"removed": result.Removed,
+		"failed":  result.Failed,
+		"ip":      c.ClientIP(),
+	}).Info("Word lists reloaded")
+
+	c.JSON(http.StatusOK, result)
+}
+
+// TopTerms lists the terms matched most often over a recent window, so the
+// lists can be tuned against real traffic. The window defaults to an hour and
+// the limit to 10.
+func (ac *AdminController) TopTerms(c *gin.Context) {
+	logger.InfoLogger.Info("TopTerms handler called")
+
+	req := topTermsRequest{Window: "1h", Limit: 10}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	window, err := time.ParseDuration(req.Window)
+	if err != nil || window <= 0 || window > metrics.MaxWindow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window must be a positive duration of at most " + metrics.MaxWindow.String()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"window": window.String(),
+		"terms":  metrics.TopTerms(window, req.Limit),
+	})
+}
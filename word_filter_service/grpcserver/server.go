@@ -0,0 +1,254 @@
+// Package grpcserver serves the word filter over gRPC, next to the HTTP routes.
+// Requests are converted to the same types the HTTP handlers bind, and checked
+// with the same validation rules.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/joy095/word-filter/badwords"
+	"github.com/joy095/word-filter/logger"
+	"github.com/joy095/word-filter/metrics"
+	wordfilterv1 "github.com/joy095/word-filter/proto/wordfilter/v1"
+
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements wordfilterv1.WordFilterServer
+type Server struct {
+	wordfilterv1.UnimplementedWordFilterServer
+}
+
+// NewServer creates a new Server
+func NewServer() *Server {
+	return &Server{}
+}
+
+// Serve listens on addr and serves the word filter until the listener fails
+func Serve(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer(
+		grpc.MaxRecvMsgSize(badwords.MaxBatchBodyBytes),
+		grpc.UnaryInterceptor(metrics.UnaryServerInterceptor()),
+	)
+	wordfilterv1.RegisterWordFilterServer(server, NewServer())
+	return server.Serve(listener)
+}
+
+var modes = map[wordfilterv1.Mode]string{
+	wordfilterv1.Mode_MODE_UNSPECIFIED: "",
+	wordfilterv1.Mode_MODE_WORD:        badwords.ModeWord,
+	wordfilterv1.Mode_MODE_SUBSTRING:   badwords.ModeSubstring,
+}
+
+var maskStyles = map[wordfilterv1.MaskStyle]string{
+	wordfilterv1.MaskStyle_MASK_STYLE_UNSPECIFIED:  "",
+	wordfilterv1.MaskStyle_MASK_STYLE_FULL:         badwords.MaskFull,
+	wordfilterv1.MaskStyle_MASK_STYLE_FIRST_LETTER: badwords.MaskFirstLetter,
+	wordfilterv1.MaskStyle_MASK_STYLE_FIXED:        badwords.MaskFixed,
+}
+
+// Check reports whether text contains bad words
+func (s *Server) Check(ctx context.Context, req *wordfilterv1.CheckRequest) (*wordfilterv1.CheckResponse, error) {
+	logger.InfoLogger.Info("Check RPC called")
+
+	request, err := checkRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := binding.Validator.ValidateStruct(&request); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response, err := badwords.CheckText(request)
+	if err != nil {
+		return nil, statusFor(err)
+	}
+	return checkResponse(response), nil
+}
+
+// Censor reports every bad word and returns the text with them masked
+func (s *Server) Censor(ctx context.Context, req *wordfilterv1.CensorRequest) (*wordfilterv1.CensorResponse, error) {
+	logger.InfoLogger.Info("Censor RPC called")
+
+	mode, ok := modes[req.GetMode()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown mode %d", req.GetMode())
+	}
+	maskStyle, ok := maskStyles[req.GetMaskStyle()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown mask style %d", req.GetMaskStyle())
+	}
+
+	request := badwords.CensorRequest{
+		Text:          req.GetText(),
+		Mode:          mode,
+		Normalization: normalization(req.GetNormalization()),
+		Languages:     req.GetLanguages(),
+		MaskStyle:     maskStyle,
+		Replacement:   req.GetReplacement(),
+		Filter:        filter(req.GetFilter()),
+	}
+	if err := binding.Validator.ValidateStruct(&request); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response, err := badwords.CensorText(request)
+	if err != nil {
+		return nil, statusFor(err)
+	}
+	return &wordfilterv1.CensorResponse{
+		ContainsBadWords: response.ContainsBadWords,
+		Languages:        response.Languages,
+		Matches:          matches(response.Matches),
+		Allowed:          matches(response.Allowed),
+		Censored:         response.Censored,
+	}, nil
+}
+
+// CheckBatch checks several keyed texts at once
+func (s *Server) CheckBatch(ctx context.Context, req *wordfilterv1.CheckBatchRequest) (*wordfilterv1.CheckBatchResponse, error) {
+	logger.InfoLogger.Info("CheckBatch RPC called")
+
+	var request badwords.BatchRequest
+	for _, item := range req.GetItems() {
+		check, err := checkRequest(item.GetRequest())
+		if err != nil {
+			return nil, err
+		}
+		request.Items = append(request.Items, badwords.BatchItem{Key: item.GetKey(), BadWordRequest: check})
+	}
+	if err := binding.Validator.ValidateStruct(&request); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response, err := badwords.CheckBatch(request)
+	if err != nil {
+		return nil, statusFor(err)
+	}
+
+	results := make(map[string]*wordfilterv1.BatchResult, len(response.Results))
+	for key, result := range response.Results {
+		if result.Result == nil {
+			results[key] = &wordfilterv1.BatchResult{Outcome: &wordfilterv1.BatchResult_Error{Error: result.Error}}
+			continue
+		}
+		results[key] = &wordfilterv1.BatchResult{Outcome: &wordfilterv1.BatchResult_Result{Result: checkResponse(*result.Result)}}
+	}
+	return &wordfilterv1.CheckBatchResponse{
+		ContainsBadWords: response.ContainsBadWords,
+		Results:          results,
+	}, nil
+}
+
+// statusFor maps errors from the badwords package to gRPC status codes
+func statusFor(err error) error {
+	switch {
+	case errors.Is(err, badwords.ErrUnknownLanguage), errors.Is(err, badwords.ErrDuplicateKey):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, badwords.ErrBatchTooLarge):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		logger.ErrorLogger.Error(err.Error())
+		return status.Error(codes.Internal, "failed to check text")
+	}
+}
+
+func checkRequest(req *wordfilterv1.CheckRequest) (badwords.BadWordRequest, error) {
+	mode, ok := modes[req.GetMode()]
+	if !ok {
+		return badwords.BadWordRequest{}, status.Errorf(codes.InvalidArgument, "unknown mode %d", req.GetMode())
+	}
+
+	return badwords.BadWordRequest{
+		Text:          req.GetText(),
+		Mode:          mode,
+		Normalization: normalization(req.GetNormalization()),
+		Languages:     req.GetLanguages(),
+		Filter:        filter(req.GetFilter()),
+	}, nil
+}
+
+func checkResponse(response badwords.BadWordResponse) *wordfilterv1.CheckResponse {
+	categories := make(map[string]*wordfilterv1.CategoryHits, len(response.Categories))
+	for category, hits := range response.Categories {
+		categories[category] = &wordfilterv1.CategoryHits{
+			Hits:        int32(hits.Hits),
+			MaxSeverity: int32(hits.MaxSeverity),
+		}
+	}
+
+	result := &wordfilterv1.CheckResponse{
+		ContainsBadWords: response.ContainsBadWords,
+		Languages:        response.Languages,
+		Categories:       categories,
+		Score:            int32(response.Score),
+		Flags: &wordfilterv1.Flags{
+			Repetition:     response.Flags.Repetition,
+			Shouting:       response.Flags.Shouting,
+			Url:            response.Flags.URL,
+			Email:          response.Flags.Email,
+			Phone:          response.Flags.Phone,
+			BlockedDomains: response.Flags.BlockedDomains,
+			Invisible:      response.Flags.Invisible,
+			MixedScript:    response.Flags.MixedScript,
+		},
+	}
+	if response.Match != nil {
+		result.Match = match(*response.Match)
+	}
+	return result
+}
+
+func normalization(n *wordfilterv1.Normalization) *badwords.Normalization {
+	if n == nil {
+		return nil
+	}
+	return &badwords.Normalization{
+		NFKC:       n.Nfkc,
+		Homoglyphs: n.Homoglyphs,
+		Leet:       n.Leet,
+		Separators: n.Separators,
+		Repeats:    n.Repeats,
+	}
+}
+
+func filter(f *wordfilterv1.Filter) badwords.Filter {
+	return badwords.Filter{
+		MinSeverity: int(f.GetMinSeverity()),
+		Categories:  f.GetCategories(),
+	}
+}
+
+func match(m badwords.Match) *wordfilterv1.Match {
+	return &wordfilterv1.Match{
+		Term:      m.Term,
+		Start:     int32(m.Start),
+		End:       int32(m.End),
+		RuneStart: int32(m.RuneStart),
+		RuneEnd:   int32(m.RuneEnd),
+		Language:  m.Language,
+		Rule:      m.Rule,
+		Category:  m.Category,
+		Severity:  int32(m.Severity),
+		AllowedBy: m.AllowedBy,
+		AllowRule: m.AllowRule,
+	}
+}
+
+func matches(ms []badwords.Match) []*wordfilterv1.Match {
+	converted := make([]*wordfilterv1.Match, len(ms))
+	for i, m := range ms {
+		converted[i] = match(m)
+	}
+	return converted
+}
@@ -50,6 +50,8 @@ func NewLogger(config LoggerConfig) *logrus.Logger {
 var (
 	InfoLogger  *logrus.Entry
 	ErrorLogger *logrus.Entry
+	// AuditLogger records every change an admin makes to the word lists
+	AuditLogger *logrus.Entry
 )
 
 // InitLoggers initializes both info and error loggers
@@ -59,7 +61,7 @@ func InitLoggers() {
 		os.Mkdir("logs", 0755) // Create logs directory if missing
 	}
 
-	serviceName := "identity-service"
+	serviceName := "word-filter-service"
 
 	// Create base loggers
 	infoBaseLogger := NewLogger(LoggerConfig{
@@ -80,7 +82,17 @@ func InitLoggers() {
 		ServiceName: serviceName,
 	})
 
+	auditBaseLogger := NewLogger(LoggerConfig{
+		Filename:    "logs/audit.log",
+		MaxSize:     10,
+		MaxBackups:  20,
+		MaxAge:      365,
+		Level:       logrus.InfoLevel,
+		ServiceName: serviceName,
+	})
+
 	// Attach service name field
 	InfoLogger = infoBaseLogger.WithField("word-filter-service", serviceName)
 	ErrorLogger = errorBaseLogger.WithField("word-filter-service", serviceName)
+	AuditLogger = auditBaseLogger.WithField("word-filter-service", serviceName)
 }
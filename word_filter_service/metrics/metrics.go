@@ -0,0 +1,100 @@
+// Package metrics exposes how the filter is used: request counts and
+// latencies for the HTTP and gRPC APIs, and how often each term and category
+// is hit, both as Prometheus metrics and as a top terms list over a recent
+// window for moderators tuning the word lists.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// latencyBuckets suit a filter that answers most requests in a millisecond or two
+var latencyBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wordfilter_http_requests_total",
+		Help: "HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "wordfilter_http_request_duration_seconds",
+		Help:    "How long HTTP requests took, by method and route.",
+		Buckets: latencyBuckets,
+	}, []string{"method", "route"})
+
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wordfilter_grpc_requests_total",
+		Help: "gRPC calls handled, by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "wordfilter_grpc_request_duration_seconds",
+		Help:    "How long gRPC calls took, by method.",
+		Buckets: latencyBuckets,
+	}, []string{"method"})
+
+	termHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wordfilter_term_hits_total",
+		Help: "Matches of each dictionary term, by language, term and category.",
+	}, []string{"language", "term", "category"})
+
+	categoryHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wordfilter_category_hits_total",
+		Help: "Matches in each category.",
+	}, []string{"category"})
+)
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// GinMiddleware counts and times every HTTP request. Requests that match no
+// route share the route label "unmatched", so stray paths don't add series.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+		c.Next()
+		duration := time.Since(startTime)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route).Observe(duration.Seconds())
+	}
+}
+
+// UnaryServerInterceptor counts and times every unary gRPC call
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		startTime := time.Now()
+		response, err := handler(ctx, req)
+		duration := time.Since(startTime)
+
+		grpcRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		grpcDuration.WithLabelValues(info.FullMethod).Observe(duration.Seconds())
+		return response, err
+	}
+}
+
+// RecordHit counts one match of a dictionary term. term is the dictionary
+// entry that matched, not the text it matched, so the series stay bounded by
+// the word lists.
+func RecordHit(language, term, category string) {
+	termHits.WithLabelValues(language, term, category).Inc()
+	categoryHits.WithLabelValues(category).Inc()
+	terms.add(termKey{Language: language, Term: term, Category: category}, time.Now())
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Term hits are kept per minute for the last day, so the top terms can be
+// asked for over any window up to MaxWindow
+const (
+	BucketWidth = time.Minute
+	MaxWindow   = 24 * time.Hour
+)
+
+// TermHits is how often one term matched within a window
+type TermHits struct {
+	Term     string `json:"term"`
+	Language string `json:"language"`
+	Category string `json:"category"`
+	Hits     int64  `json:"hits"`
+}
+
+type termKey struct {
+	Language string
+	Term     string
+	Category string
+}
+
+// termBucket holds the hits of one BucketWidth slice of time
+type termBucket struct {
+	start time.Time
+	hits  map[termKey]int64
+}
+
+// termWindow is a ring of buckets covering the last MaxWindow. A bucket is
+// reused once the time it covered has fallen out of the window.
+type termWindow struct {
+	mu      sync.Mutex
+	buckets []termBucket
+}
+
+var terms = newTermWindow()
+
+func newTermWindow() *termWindow {
+	return &termWindow{buckets: make([]termBucket, MaxWindow/BucketWidth)}
+}
+
+func (w *termWindow) add(key termKey, now time.Time) {
+	start := now.Truncate(BucketWidth)
+	index := int(start.UnixNano()/int64(BucketWidth)) % len(w.buckets)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	bucket := &w.buckets[index]
+	if !bucket.start.Equal(start) {
+		bucket.start = start
+		bucket.hits = make(map[termKey]int64)
+	}
+	bucket.hits[key]++
+}
+
+// top returns the n terms hit most since now minus window, most hit first
+func (w *termWindow) top(window time.Duration, n int, now time.Time) []TermHits {
+	// Windows cover whole buckets, the current one included, so a one minute
+	// window isn't empty right after the minute turns over
+	window = (window + BucketWidth - 1).Truncate(BucketWidth)
+	since := now.Truncate(BucketWidth).Add(BucketWidth - window)
+
+	totals := make(map[termKey]int64)
+	w.mu.Lock()
+	for _, bucket := range w.buckets {
+		if bucket.hits == nil || bucket.start.Before(since) || bucket.start.After(now) {
+			continue
+		}
+		for key, hits := range bucket.hits {
+			totals[key] += hits
+		}
+	}
+	w.mu.Unlock()
+
+	top := make([]TermHits, 0, len(totals))
+	for key, hits := range totals {
+		top = append(top, TermHits{Term: key.Term, Language: key.Language, Category: key.Category, Hits: hits})
+	}
+	slices.SortFunc(top, func(a, b TermHits) int {
+		return cmp.Or(
+			cmp.Compare(b.Hits, a.Hits),
+			cmp.Compare(a.Language, b.Language),
+			cmp.Compare(a.Term, b.Term),
+		)
+	})
+	return top[:min(n, len(top))]
+}
+
+// TopTerms returns the n terms matched most often over the last window, most
+// matched first. window is rounded up to whole minutes and capped at MaxWindow.
+func TopTerms(window time.Duration, n int) []TermHits {
+	return terms.top(min(window, MaxWindow), n, time.Now())
+}
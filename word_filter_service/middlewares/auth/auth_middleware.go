@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/joy095/word-filter/logger"
+	"github.com/joy095/word-filter/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ValidateToken parses an HS256 access token issued by identity_service and
+// returns the user ID stored in its claims
+func ValidateToken(tokenString string) (string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		// Ensure the token method is what identity_service signs with
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		secret := utils.GetJWTSecret()
+		if secret == nil {
+			return nil, errors.New("JWT_SECRET is not set")
+		}
+		return secret, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !token.Valid {
+		return "", errors.New("token is not valid")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", errors.New("invalid token claims")
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok || userID == "" {
+		return "", errors.New("invalid token claims: user_id not found")
+	}
+
+	return userID, nil
+}
+
+// AdminMiddleware only lets through requests with a valid access token that
+// belongs to an admin, and stores the user ID in the context
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if tokenString == "" {
+			logger.ErrorLogger.Error("Authorization header required")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Abort()
+			return
+		}
+
+		userID, err := ValidateToken(tokenString)
+		if err != nil {
+			logger.ErrorLogger.Errorf("Error parsing token: %v", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		if !utils.IsAdmin(userID) {
+			logger.ErrorLogger.Errorf("User %s is not an admin", userID)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Next()
+	}
+}
@@ -0,0 +1,12 @@
+// Package wordfilterv1 is the generated gRPC client and server code for
+// word_filter_service. Other services import it to call the filter with typed
+// requests and deadlines:
+//
+//	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	client := wordfilterv1.NewWordFilterClient(conn)
+//	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+//	defer cancel()
+//	response, err := client.Check(ctx, &wordfilterv1.CheckRequest{Text: username})
+package wordfilterv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative wordfilter.proto
@@ -0,0 +1,1330 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: wordfilter.proto
+
+package wordfilterv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Mode int32
+
+const (
+	// Same as MODE_WORD
+	Mode_MODE_UNSPECIFIED Mode = 0
+	// A term only matches as a whole word or phrase
+	Mode_MODE_WORD Mode = 1
+	// A term matches anywhere, even inside other words
+	Mode_MODE_SUBSTRING Mode = 2
+)
+
+// Enum value maps for Mode.
+var (
+	Mode_name = map[int32]string{
+		0: "MODE_UNSPECIFIED",
+		1: "MODE_WORD",
+		2: "MODE_SUBSTRING",
+	}
+	Mode_value = map[string]int32{
+		"MODE_UNSPECIFIED": 0,
+		"MODE_WORD":        1,
+		"MODE_SUBSTRING":   2,
+	}
+)
+
+func (x Mode) Enum() *Mode {
+	p := new(Mode)
+	*p = x
+	return p
+}
+
+func (x Mode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Mode) Descriptor() protoreflect.EnumDescriptor {
+	return file_wordfilter_proto_enumTypes[0].Descriptor()
+}
+
+func (Mode) Type() protoreflect.EnumType {
+	return &file_wordfilter_proto_enumTypes[0]
+}
+
+func (x Mode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Mode.Descriptor instead.
+func (Mode) EnumDescriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{0}
+}
+
+type MaskStyle int32
+
+const (
+	// Same as MASK_STYLE_FULL
+	MaskStyle_MASK_STYLE_UNSPECIFIED MaskStyle = 0
+	// Replace every character with '*'
+	MaskStyle_MASK_STYLE_FULL MaskStyle = 1
+	// Keep the first letter and mask the rest
+	MaskStyle_MASK_STYLE_FIRST_LETTER MaskStyle = 2
+	// Replace the whole term with the request's replacement
+	MaskStyle_MASK_STYLE_FIXED MaskStyle = 3
+)
+
+// Enum value maps for MaskStyle.
+var (
+	MaskStyle_name = map[int32]string{
+		0: "MASK_STYLE_UNSPECIFIED",
+		1: "MASK_STYLE_FULL",
+		2: "MASK_STYLE_FIRST_LETTER",
+		3: "MASK_STYLE_FIXED",
+	}
+	MaskStyle_value = map[string]int32{
+		"MASK_STYLE_UNSPECIFIED":  0,
+		"MASK_STYLE_FULL":         1,
+		"MASK_STYLE_FIRST_LETTER": 2,
+		"MASK_STYLE_FIXED":        3,
+	}
+)
+
+func (x MaskStyle) Enum() *MaskStyle {
+	p := new(MaskStyle)
+	*p = x
+	return p
+}
+
+func (x MaskStyle) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MaskStyle) Descriptor() protoreflect.EnumDescriptor {
+	return file_wordfilter_proto_enumTypes[1].Descriptor()
+}
+
+func (MaskStyle) Type() protoreflect.EnumType {
+	return &file_wordfilter_proto_enumTypes[1]
+}
+
+func (x MaskStyle) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MaskStyle.Descriptor instead.
+func (MaskStyle) EnumDescriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{1}
+}
+
+// Switches normalization stages on or off; stages left unset stay on
+type Normalization struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nfkc          *bool                  `protobuf:"varint,1,opt,name=nfkc,proto3,oneof" json:"nfkc,omitempty"`
+	Homoglyphs    *bool                  `protobuf:"varint,2,opt,name=homoglyphs,proto3,oneof" json:"homoglyphs,omitempty"`
+	Leet          *bool                  `protobuf:"varint,3,opt,name=leet,proto3,oneof" json:"leet,omitempty"`
+	Separators    *bool                  `protobuf:"varint,4,opt,name=separators,proto3,oneof" json:"separators,omitempty"`
+	Repeats       *bool                  `protobuf:"varint,5,opt,name=repeats,proto3,oneof" json:"repeats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Normalization) Reset() {
+	*x = Normalization{}
+	mi := &file_wordfilter_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Normalization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Normalization) ProtoMessage() {}
+
+func (x *Normalization) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Normalization.ProtoReflect.Descriptor instead.
+func (*Normalization) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Normalization) GetNfkc() bool {
+	if x != nil && x.Nfkc != nil {
+		return *x.Nfkc
+	}
+	return false
+}
+
+func (x *Normalization) GetHomoglyphs() bool {
+	if x != nil && x.Homoglyphs != nil {
+		return *x.Homoglyphs
+	}
+	return false
+}
+
+func (x *Normalization) GetLeet() bool {
+	if x != nil && x.Leet != nil {
+		return *x.Leet
+	}
+	return false
+}
+
+func (x *Normalization) GetSeparators() bool {
+	if x != nil && x.Separators != nil {
+		return *x.Separators
+	}
+	return false
+}
+
+func (x *Normalization) GetRepeats() bool {
+	if x != nil && x.Repeats != nil {
+		return *x.Repeats
+	}
+	return false
+}
+
+// Narrows which terms count; the zero value counts everything
+type Filter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 1 to 3, or 0 for no minimum
+	MinSeverity int32 `protobuf:"varint,1,opt,name=min_severity,json=minSeverity,proto3" json:"min_severity,omitempty"`
+	// Any of profanity, slur, sexual, violence, spam; empty means all
+	Categories    []string `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Filter) Reset() {
+	*x = Filter{}
+	mi := &file_wordfilter_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Filter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Filter) ProtoMessage() {}
+
+func (x *Filter) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Filter.ProtoReflect.Descriptor instead.
+func (*Filter) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Filter) GetMinSeverity() int32 {
+	if x != nil {
+		return x.MinSeverity
+	}
+	return 0
+}
+
+func (x *Filter) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type CheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Mode          Mode                   `protobuf:"varint,2,opt,name=mode,proto3,enum=wordfilter.v1.Mode" json:"mode,omitempty"`
+	Normalization *Normalization         `protobuf:"bytes,3,opt,name=normalization,proto3" json:"normalization,omitempty"`
+	// Languages whose dictionaries are checked; empty means guess from the text
+	Languages     []string `protobuf:"bytes,4,rep,name=languages,proto3" json:"languages,omitempty"`
+	Filter        *Filter  `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRequest) Reset() {
+	*x = CheckRequest{}
+	mi := &file_wordfilter_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRequest) ProtoMessage() {}
+
+func (x *CheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRequest.ProtoReflect.Descriptor instead.
+func (*CheckRequest) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CheckRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetMode() Mode {
+	if x != nil {
+		return x.Mode
+	}
+	return Mode_MODE_UNSPECIFIED
+}
+
+func (x *CheckRequest) GetNormalization() *Normalization {
+	if x != nil {
+		return x.Normalization
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// One bad word found in the text. Offsets are into the original text, end exclusive.
+type Match struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Term      string                 `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	Start     int32                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End       int32                  `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	RuneStart int32                  `protobuf:"varint,4,opt,name=rune_start,json=runeStart,proto3" json:"rune_start,omitempty"`
+	RuneEnd   int32                  `protobuf:"varint,5,opt,name=rune_end,json=runeEnd,proto3" json:"rune_end,omitempty"`
+	Language  string                 `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`
+	// The list entry that fired
+	Rule     string `protobuf:"bytes,7,opt,name=rule,proto3" json:"rule,omitempty"`
+	Category string `protobuf:"bytes,8,opt,name=category,proto3" json:"category,omitempty"`
+	Severity int32  `protobuf:"varint,9,opt,name=severity,proto3" json:"severity,omitempty"`
+	// Set when an allowlist word or exception let the match through
+	AllowedBy     string `protobuf:"bytes,10,opt,name=allowed_by,json=allowedBy,proto3" json:"allowed_by,omitempty"`
+	AllowRule     string `protobuf:"bytes,11,opt,name=allow_rule,json=allowRule,proto3" json:"allow_rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Match) Reset() {
+	*x = Match{}
+	mi := &file_wordfilter_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Match) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Match) ProtoMessage() {}
+
+func (x *Match) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Match.ProtoReflect.Descriptor instead.
+func (*Match) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Match) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *Match) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Match) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *Match) GetRuneStart() int32 {
+	if x != nil {
+		return x.RuneStart
+	}
+	return 0
+}
+
+func (x *Match) GetRuneEnd() int32 {
+	if x != nil {
+		return x.RuneEnd
+	}
+	return 0
+}
+
+func (x *Match) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Match) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *Match) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Match) GetSeverity() int32 {
+	if x != nil {
+		return x.Severity
+	}
+	return 0
+}
+
+func (x *Match) GetAllowedBy() string {
+	if x != nil {
+		return x.AllowedBy
+	}
+	return ""
+}
+
+func (x *Match) GetAllowRule() string {
+	if x != nil {
+		return x.AllowRule
+	}
+	return ""
+}
+
+type CategoryHits struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          int32                  `protobuf:"varint,1,opt,name=hits,proto3" json:"hits,omitempty"`
+	MaxSeverity   int32                  `protobuf:"varint,2,opt,name=max_severity,json=maxSeverity,proto3" json:"max_severity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryHits) Reset() {
+	*x = CategoryHits{}
+	mi := &file_wordfilter_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryHits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryHits) ProtoMessage() {}
+
+func (x *CategoryHits) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryHits.ProtoReflect.Descriptor instead.
+func (*CategoryHits) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CategoryHits) GetHits() int32 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CategoryHits) GetMaxSeverity() int32 {
+	if x != nil {
+		return x.MaxSeverity
+	}
+	return 0
+}
+
+type CheckResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ContainsBadWords bool                   `protobuf:"varint,1,opt,name=contains_bad_words,json=containsBadWords,proto3" json:"contains_bad_words,omitempty"`
+	Languages        []string               `protobuf:"bytes,2,rep,name=languages,proto3" json:"languages,omitempty"`
+	// The most severe match, if any
+	Match      *Match                   `protobuf:"bytes,3,opt,name=match,proto3" json:"match,omitempty"`
+	Categories map[string]*CategoryHits `protobuf:"bytes,4,rep,name=categories,proto3" json:"categories,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// The severity of every match added up
+	Score int32 `protobuf:"varint,5,opt,name=score,proto3" json:"score,omitempty"`
+	// Spam heuristics, reported whether or not the text has bad words
+	Flags         *Flags `protobuf:"bytes,6,opt,name=flags,proto3" json:"flags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckResponse) Reset() {
+	*x = CheckResponse{}
+	mi := &file_wordfilter_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckResponse) ProtoMessage() {}
+
+func (x *CheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckResponse.ProtoReflect.Descriptor instead.
+func (*CheckResponse) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CheckResponse) GetContainsBadWords() bool {
+	if x != nil {
+		return x.ContainsBadWords
+	}
+	return false
+}
+
+func (x *CheckResponse) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *CheckResponse) GetMatch() *Match {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *CheckResponse) GetCategories() map[string]*CategoryHits {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *CheckResponse) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *CheckResponse) GetFlags() *Flags {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+// Signals about text that the dictionaries can't see
+type Flags struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A character or word repeated many times in a row
+	Repetition bool `protobuf:"varint,1,opt,name=repetition,proto3" json:"repetition,omitempty"`
+	// Mostly capital letters
+	Shouting bool `protobuf:"varint,2,opt,name=shouting,proto3" json:"shouting,omitempty"`
+	Url      bool `protobuf:"varint,3,opt,name=url,proto3" json:"url,omitempty"`
+	Email    bool `protobuf:"varint,4,opt,name=email,proto3" json:"email,omitempty"`
+	Phone    bool `protobuf:"varint,5,opt,name=phone,proto3" json:"phone,omitempty"`
+	// Domains of links and emails that are on the domain blocklist
+	BlockedDomains []string `protobuf:"bytes,6,rep,name=blocked_domains,json=blockedDomains,proto3" json:"blocked_domains,omitempty"`
+	// Zero-width, bidi control or other invisible formatting characters
+	Invisible bool `protobuf:"varint,7,opt,name=invisible,proto3" json:"invisible,omitempty"`
+	// A word mixing letters of several scripts
+	MixedScript   bool `protobuf:"varint,8,opt,name=mixed_script,json=mixedScript,proto3" json:"mixed_script,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Flags) Reset() {
+	*x = Flags{}
+	mi := &file_wordfilter_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Flags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flags) ProtoMessage() {}
+
+func (x *Flags) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flags.ProtoReflect.Descriptor instead.
+func (*Flags) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Flags) GetRepetition() bool {
+	if x != nil {
+		return x.Repetition
+	}
+	return false
+}
+
+func (x *Flags) GetShouting() bool {
+	if x != nil {
+		return x.Shouting
+	}
+	return false
+}
+
+func (x *Flags) GetUrl() bool {
+	if x != nil {
+		return x.Url
+	}
+	return false
+}
+
+func (x *Flags) GetEmail() bool {
+	if x != nil {
+		return x.Email
+	}
+	return false
+}
+
+func (x *Flags) GetPhone() bool {
+	if x != nil {
+		return x.Phone
+	}
+	return false
+}
+
+func (x *Flags) GetBlockedDomains() []string {
+	if x != nil {
+		return x.BlockedDomains
+	}
+	return nil
+}
+
+func (x *Flags) GetInvisible() bool {
+	if x != nil {
+		return x.Invisible
+	}
+	return false
+}
+
+func (x *Flags) GetMixedScript() bool {
+	if x != nil {
+		return x.MixedScript
+	}
+	return false
+}
+
+type CensorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Mode          Mode                   `protobuf:"varint,2,opt,name=mode,proto3,enum=wordfilter.v1.Mode" json:"mode,omitempty"`
+	Normalization *Normalization         `protobuf:"bytes,3,opt,name=normalization,proto3" json:"normalization,omitempty"`
+	Languages     []string               `protobuf:"bytes,4,rep,name=languages,proto3" json:"languages,omitempty"`
+	Filter        *Filter                `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
+	MaskStyle     MaskStyle              `protobuf:"varint,6,opt,name=mask_style,json=maskStyle,proto3,enum=wordfilter.v1.MaskStyle" json:"mask_style,omitempty"`
+	// Used by MASK_STYLE_FIXED; defaults to "****"
+	Replacement   string `protobuf:"bytes,7,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CensorRequest) Reset() {
+	*x = CensorRequest{}
+	mi := &file_wordfilter_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CensorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CensorRequest) ProtoMessage() {}
+
+func (x *CensorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CensorRequest.ProtoReflect.Descriptor instead.
+func (*CensorRequest) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CensorRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CensorRequest) GetMode() Mode {
+	if x != nil {
+		return x.Mode
+	}
+	return Mode_MODE_UNSPECIFIED
+}
+
+func (x *CensorRequest) GetNormalization() *Normalization {
+	if x != nil {
+		return x.Normalization
+	}
+	return nil
+}
+
+func (x *CensorRequest) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *CensorRequest) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *CensorRequest) GetMaskStyle() MaskStyle {
+	if x != nil {
+		return x.MaskStyle
+	}
+	return MaskStyle_MASK_STYLE_UNSPECIFIED
+}
+
+func (x *CensorRequest) GetReplacement() string {
+	if x != nil {
+		return x.Replacement
+	}
+	return ""
+}
+
+type CensorResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ContainsBadWords bool                   `protobuf:"varint,1,opt,name=contains_bad_words,json=containsBadWords,proto3" json:"contains_bad_words,omitempty"`
+	Languages        []string               `protobuf:"bytes,2,rep,name=languages,proto3" json:"languages,omitempty"`
+	Matches          []*Match               `protobuf:"bytes,3,rep,name=matches,proto3" json:"matches,omitempty"`
+	// Matches that allow rules let through
+	Allowed       []*Match `protobuf:"bytes,4,rep,name=allowed,proto3" json:"allowed,omitempty"`
+	Censored      string   `protobuf:"bytes,5,opt,name=censored,proto3" json:"censored,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CensorResponse) Reset() {
+	*x = CensorResponse{}
+	mi := &file_wordfilter_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CensorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CensorResponse) ProtoMessage() {}
+
+func (x *CensorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CensorResponse.ProtoReflect.Descriptor instead.
+func (*CensorResponse) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CensorResponse) GetContainsBadWords() bool {
+	if x != nil {
+		return x.ContainsBadWords
+	}
+	return false
+}
+
+func (x *CensorResponse) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *CensorResponse) GetMatches() []*Match {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *CensorResponse) GetAllowed() []*Match {
+	if x != nil {
+		return x.Allowed
+	}
+	return nil
+}
+
+func (x *CensorResponse) GetCensored() string {
+	if x != nil {
+		return x.Censored
+	}
+	return ""
+}
+
+type CheckBatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 100 items with unique keys
+	Items         []*BatchItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckBatchRequest) Reset() {
+	*x = CheckBatchRequest{}
+	mi := &file_wordfilter_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckBatchRequest) ProtoMessage() {}
+
+func (x *CheckBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckBatchRequest.ProtoReflect.Descriptor instead.
+func (*CheckBatchRequest) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CheckBatchRequest) GetItems() []*BatchItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type BatchItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Names the item in the response, such as "displayName" or "bio"
+	Key           string        `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Request       *CheckRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchItem) Reset() {
+	*x = BatchItem{}
+	mi := &file_wordfilter_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItem) ProtoMessage() {}
+
+func (x *BatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItem.ProtoReflect.Descriptor instead.
+func (*BatchItem) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchItem) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *BatchItem) GetRequest() *CheckRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type CheckBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// True if any item contains bad words
+	ContainsBadWords bool                    `protobuf:"varint,1,opt,name=contains_bad_words,json=containsBadWords,proto3" json:"contains_bad_words,omitempty"`
+	Results          map[string]*BatchResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CheckBatchResponse) Reset() {
+	*x = CheckBatchResponse{}
+	mi := &file_wordfilter_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckBatchResponse) ProtoMessage() {}
+
+func (x *CheckBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckBatchResponse.ProtoReflect.Descriptor instead.
+func (*CheckBatchResponse) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CheckBatchResponse) GetContainsBadWords() bool {
+	if x != nil {
+		return x.ContainsBadWords
+	}
+	return false
+}
+
+func (x *CheckBatchResponse) GetResults() map[string]*BatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// The outcome of one item: the check's response, or why it couldn't be checked
+type BatchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Outcome:
+	//
+	//	*BatchResult_Result
+	//	*BatchResult_Error
+	Outcome       isBatchResult_Outcome `protobuf_oneof:"outcome"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_wordfilter_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_wordfilter_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_wordfilter_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchResult) GetOutcome() isBatchResult_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *BatchResult) GetResult() *CheckResponse {
+	if x != nil {
+		if x, ok := x.Outcome.(*BatchResult_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *BatchResult) GetError() string {
+	if x != nil {
+		if x, ok := x.Outcome.(*BatchResult_Error); ok {
+			return x.Error
+		}
+	}
+	return ""
+}
+
+type isBatchResult_Outcome interface {
+	isBatchResult_Outcome()
+}
+
+type BatchResult_Result struct {
+	Result *CheckResponse `protobuf:"bytes,1,opt,name=result,proto3,oneof"`
+}
+
+type BatchResult_Error struct {
+	Error string `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchResult_Result) isBatchResult_Outcome() {}
+
+func (*BatchResult_Error) isBatchResult_Outcome() {}
+
+var File_wordfilter_proto protoreflect.FileDescriptor
+
+const file_wordfilter_proto_rawDesc = "" +
+	"\n" +
+	"\x10wordfilter.proto\x12\rwordfilter.v1\"\xe6\x01\n" +
+	"\rNormalization\x12\x17\n" +
+	"\x04nfkc\x18\x01 \x01(\bH\x00R\x04nfkc\x88\x01\x01\x12#\n" +
+	"\n" +
+	"homoglyphs\x18\x02 \x01(\bH\x01R\n" +
+	"homoglyphs\x88\x01\x01\x12\x17\n" +
+	"\x04leet\x18\x03 \x01(\bH\x02R\x04leet\x88\x01\x01\x12#\n" +
+	"\n" +
+	"separators\x18\x04 \x01(\bH\x03R\n" +
+	"separators\x88\x01\x01\x12\x1d\n" +
+	"\arepeats\x18\x05 \x01(\bH\x04R\arepeats\x88\x01\x01B\a\n" +
+	"\x05_nfkcB\r\n" +
+	"\v_homoglyphsB\a\n" +
+	"\x05_leetB\r\n" +
+	"\v_separatorsB\n" +
+	"\n" +
+	"\b_repeats\"K\n" +
+	"\x06Filter\x12!\n" +
+	"\fmin_severity\x18\x01 \x01(\x05R\vminSeverity\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x02 \x03(\tR\n" +
+	"categories\"\xdc\x01\n" +
+	"\fCheckRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12'\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x13.wordfilter.v1.ModeR\x04mode\x12B\n" +
+	"\rnormalization\x18\x03 \x01(\v2\x1c.wordfilter.v1.NormalizationR\rnormalization\x12\x1c\n" +
+	"\tlanguages\x18\x04 \x03(\tR\tlanguages\x12-\n" +
+	"\x06filter\x18\x05 \x01(\v2\x15.wordfilter.v1.FilterR\x06filter\"\xa3\x02\n" +
+	"\x05Match\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\tR\x04term\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\x05R\x03end\x12\x1d\n" +
+	"\n" +
+	"rune_start\x18\x04 \x01(\x05R\truneStart\x12\x19\n" +
+	"\brune_end\x18\x05 \x01(\x05R\aruneEnd\x12\x1a\n" +
+	"\blanguage\x18\x06 \x01(\tR\blanguage\x12\x12\n" +
+	"\x04rule\x18\a \x01(\tR\x04rule\x12\x1a\n" +
+	"\bcategory\x18\b \x01(\tR\bcategory\x12\x1a\n" +
+	"\bseverity\x18\t \x01(\x05R\bseverity\x12\x1d\n" +
+	"\n" +
+	"allowed_by\x18\n" +
+	" \x01(\tR\tallowedBy\x12\x1d\n" +
+	"\n" +
+	"allow_rule\x18\v \x01(\tR\tallowRule\"E\n" +
+	"\fCategoryHits\x12\x12\n" +
+	"\x04hits\x18\x01 \x01(\x05R\x04hits\x12!\n" +
+	"\fmax_severity\x18\x02 \x01(\x05R\vmaxSeverity\"\xf3\x02\n" +
+	"\rCheckResponse\x12,\n" +
+	"\x12contains_bad_words\x18\x01 \x01(\bR\x10containsBadWords\x12\x1c\n" +
+	"\tlanguages\x18\x02 \x03(\tR\tlanguages\x12*\n" +
+	"\x05match\x18\x03 \x01(\v2\x14.wordfilter.v1.MatchR\x05match\x12L\n" +
+	"\n" +
+	"categories\x18\x04 \x03(\v2,.wordfilter.v1.CheckResponse.CategoriesEntryR\n" +
+	"categories\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x05R\x05score\x12*\n" +
+	"\x05flags\x18\x06 \x01(\v2\x14.wordfilter.v1.FlagsR\x05flags\x1aZ\n" +
+	"\x0fCategoriesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x121\n" +
+	"\x05value\x18\x02 \x01(\v2\x1b.wordfilter.v1.CategoryHitsR\x05value:\x028\x01\"\xeb\x01\n" +
+	"\x05Flags\x12\x1e\n" +
+	"\n" +
+	"repetition\x18\x01 \x01(\bR\n" +
+	"repetition\x12\x1a\n" +
+	"\bshouting\x18\x02 \x01(\bR\bshouting\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\bR\x03url\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\bR\x05email\x12\x14\n" +
+	"\x05phone\x18\x05 \x01(\bR\x05phone\x12'\n" +
+	"\x0fblocked_domains\x18\x06 \x03(\tR\x0eblockedDomains\x12\x1c\n" +
+	"\tinvisible\x18\a \x01(\bR\tinvisible\x12!\n" +
+	"\fmixed_script\x18\b \x01(\bR\vmixedScript\"\xb8\x02\n" +
+	"\rCensorRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12'\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x13.wordfilter.v1.ModeR\x04mode\x12B\n" +
+	"\rnormalization\x18\x03 \x01(\v2\x1c.wordfilter.v1.NormalizationR\rnormalization\x12\x1c\n" +
+	"\tlanguages\x18\x04 \x03(\tR\tlanguages\x12-\n" +
+	"\x06filter\x18\x05 \x01(\v2\x15.wordfilter.v1.FilterR\x06filter\x127\n" +
+	"\n" +
+	"mask_style\x18\x06 \x01(\x0e2\x18.wordfilter.v1.MaskStyleR\tmaskStyle\x12 \n" +
+	"\vreplacement\x18\a \x01(\tR\vreplacement\"\xd8\x01\n" +
+	"\x0eCensorResponse\x12,\n" +
+	"\x12contains_bad_words\x18\x01 \x01(\bR\x10containsBadWords\x12\x1c\n" +
+	"\tlanguages\x18\x02 \x03(\tR\tlanguages\x12.\n" +
+	"\amatches\x18\x03 \x03(\v2\x14.wordfilter.v1.MatchR\amatches\x12.\n" +
+	"\aallowed\x18\x04 \x03(\v2\x14.wordfilter.v1.MatchR\aallowed\x12\x1a\n" +
+	"\bcensored\x18\x05 \x01(\tR\bcensored\"C\n" +
+	"\x11CheckBatchRequest\x12.\n" +
+	"\x05items\x18\x01 \x03(\v2\x18.wordfilter.v1.BatchItemR\x05items\"T\n" +
+	"\tBatchItem\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x125\n" +
+	"\arequest\x18\x02 \x01(\v2\x1b.wordfilter.v1.CheckRequestR\arequest\"\xe4\x01\n" +
+	"\x12CheckBatchResponse\x12,\n" +
+	"\x12contains_bad_words\x18\x01 \x01(\bR\x10containsBadWords\x12H\n" +
+	"\aresults\x18\x02 \x03(\v2..wordfilter.v1.CheckBatchResponse.ResultsEntryR\aresults\x1aV\n" +
+	"\fResultsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.wordfilter.v1.BatchResultR\x05value:\x028\x01\"h\n" +
+	"\vBatchResult\x126\n" +
+	"\x06result\x18\x01 \x01(\v2\x1c.wordfilter.v1.CheckResponseH\x00R\x06result\x12\x16\n" +
+	"\x05error\x18\x02 \x01(\tH\x00R\x05errorB\t\n" +
+	"\aoutcome*?\n" +
+	"\x04Mode\x12\x14\n" +
+	"\x10MODE_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tMODE_WORD\x10\x01\x12\x12\n" +
+	"\x0eMODE_SUBSTRING\x10\x02*o\n" +
+	"\tMaskStyle\x12\x1a\n" +
+	"\x16MASK_STYLE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fMASK_STYLE_FULL\x10\x01\x12\x1b\n" +
+	"\x17MASK_STYLE_FIRST_LETTER\x10\x02\x12\x14\n" +
+	"\x10MASK_STYLE_FIXED\x10\x032\xea\x01\n" +
+	"\n" +
+	"WordFilter\x12B\n" +
+	"\x05Check\x12\x1b.wordfilter.v1.CheckRequest\x1a\x1c.wordfilter.v1.CheckResponse\x12E\n" +
+	"\x06Censor\x12\x1c.wordfilter.v1.CensorRequest\x1a\x1d.wordfilter.v1.CensorResponse\x12Q\n" +
+	"\n" +
+	"CheckBatch\x12 .wordfilter.v1.CheckBatchRequest\x1a!.wordfilter.v1.CheckBatchResponseB@Z>github.com/joy095/word-filter/proto/wordfilter/v1;wordfilterv1b\x06proto3"
+
+var (
+	file_wordfilter_proto_rawDescOnce sync.Once
+	file_wordfilter_proto_rawDescData []byte
+)
+
+func file_wordfilter_proto_rawDescGZIP() []byte {
+	file_wordfilter_proto_rawDescOnce.Do(func() {
+		file_wordfilter_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_wordfilter_proto_rawDesc), len(file_wordfilter_proto_rawDesc)))
+	})
+	return file_wordfilter_proto_rawDescData
+}
+
+var file_wordfilter_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_wordfilter_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_wordfilter_proto_goTypes = []any{
+	(Mode)(0),                  // 0: wordfilter.v1.Mode
+	(MaskStyle)(0),             // 1: wordfilter.v1.MaskStyle
+	(*Normalization)(nil),      // 2: wordfilter.v1.Normalization
+	(*Filter)(nil),             // 3: wordfilter.v1.Filter
+	(*CheckRequest)(nil),       // 4: wordfilter.v1.CheckRequest
+	(*Match)(nil),              // 5: wordfilter.v1.Match
+	(*CategoryHits)(nil),       // 6: wordfilter.v1.CategoryHits
+	(*CheckResponse)(nil),      // 7: wordfilter.v1.CheckResponse
+	(*Flags)(nil),              // 8: wordfilter.v1.Flags
+	(*CensorRequest)(nil),      // 9: wordfilter.v1.CensorRequest
+	(*CensorResponse)(nil),     // 10: wordfilter.v1.CensorResponse
+	(*CheckBatchRequest)(nil),  // 11: wordfilter.v1.CheckBatchRequest
+	(*BatchItem)(nil),          // 12: wordfilter.v1.BatchItem
+	(*CheckBatchResponse)(nil), // 13: wordfilter.v1.CheckBatchResponse
+	(*BatchResult)(nil),        // 14: wordfilter.v1.BatchResult
+	nil,                        // 15: wordfilter.v1.CheckResponse.CategoriesEntry
+	nil,                        // 16: wordfilter.v1.CheckBatchResponse.ResultsEntry
+}
+var file_wordfilter_proto_depIdxs = []int32{
+	0,  // 0: wordfilter.v1.CheckRequest.mode:type_name -> wordfilter.v1.Mode
+	2,  // 1: wordfilter.v1.CheckRequest.normalization:type_name -> wordfilter.v1.Normalization
+	3,  // 2: wordfilter.v1.CheckRequest.filter:type_name -> wordfilter.v1.Filter
+	5,  // 3: wordfilter.v1.CheckResponse.match:type_name -> wordfilter.v1.Match
+	15, // 4: wordfilter.v1.CheckResponse.categories:type_name -> wordfilter.v1.CheckResponse.CategoriesEntry
+	8,  // 5: wordfilter.v1.CheckResponse.flags:type_name -> wordfilter.v1.Flags
+	0,  // 6: wordfilter.v1.CensorRequest.mode:type_name -> wordfilter.v1.Mode
+	2,  // 7: wordfilter.v1.CensorRequest.normalization:type_name -> wordfilter.v1.Normalization
+	3,  // 8: wordfilter.v1.CensorRequest.filter:type_name -> wordfilter.v1.Filter
+	1,  // 9: wordfilter.v1.CensorRequest.mask_style:type_name -> wordfilter.v1.MaskStyle
+	5,  // 10: wordfilter.v1.CensorResponse.matches:type_name -> wordfilter.v1.Match
+	5,  // 11: wordfilter.v1.CensorResponse.allowed:type_name -> wordfilter.v1.Match
+	12, // 12: wordfilter.v1.CheckBatchRequest.items:type_name -> wordfilter.v1.BatchItem
+	4,  // 13: wordfilter.v1.BatchItem.request:type_name -> wordfilter.v1.CheckRequest
+	16, // 14: wordfilter.v1.CheckBatchResponse.results:type_name -> wordfilter.v1.CheckBatchResponse.ResultsEntry
+	7,  // 15: wordfilter.v1.BatchResult.result:type_name -> wordfilter.v1.CheckResponse
+	6,  // 16: wordfilter.v1.CheckResponse.CategoriesEntry.value:type_name -> wordfilter.v1.CategoryHits
+	14, // 17: wordfilter.v1.CheckBatchResponse.ResultsEntry.value:type_name -> wordfilter.v1.BatchResult
+	4,  // 18: wordfilter.v1.WordFilter.Check:input_type -> wordfilter.v1.CheckRequest
+	9,  // 19: wordfilter.v1.WordFilter.Censor:input_type -> wordfilter.v1.CensorRequest
+	11, // 20: wordfilter.v1.WordFilter.CheckBatch:input_type -> wordfilter.v1.CheckBatchRequest
+	7,  // 21: wordfilter.v1.WordFilter.Check:output_type -> wordfilter.v1.CheckResponse
+	10, // 22: wordfilter.v1.WordFilter.Censor:output_type -> wordfilter.v1.CensorResponse
+	13, // 23: wordfilter.v1.WordFilter.CheckBatch:output_type -> wordfilter.v1.CheckBatchResponse
+	21, // [21:24] is the sub-list for method output_type
+	18, // [18:21] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_wordfilter_proto_init() }
+func file_wordfilter_proto_init() {
+	if File_wordfilter_proto != nil {
+		return
+	}
+	file_wordfilter_proto_msgTypes[0].OneofWrappers = []any{}
+	file_wordfilter_proto_msgTypes[12].OneofWrappers = []any{
+		(*BatchResult_Result)(nil),
+		(*BatchResult_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_wordfilter_proto_rawDesc), len(file_wordfilter_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_wordfilter_proto_goTypes,
+		DependencyIndexes: file_wordfilter_proto_depIdxs,
+		EnumInfos:         file_wordfilter_proto_enumTypes,
+		MessageInfos:      file_wordfilter_proto_msgTypes,
+	}.Build()
+	File_wordfilter_proto = out.File
+	file_wordfilter_proto_goTypes = nil
+	file_wordfilter_proto_depIdxs = nil
+}
@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: wordfilter.proto
+
+package wordfilterv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WordFilter_Check_FullMethodName      = "/wordfilter.v1.WordFilter/Check"
+	WordFilter_Censor_FullMethodName     = "/wordfilter.v1.WordFilter/Censor"
+	WordFilter_CheckBatch_FullMethodName = "/wordfilter.v1.WordFilter/CheckBatch"
+)
+
+// WordFilterClient is the client API for WordFilter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WordFilter is the filter's contract for other services. It mirrors the HTTP
+// routes: Check is POST /check, Censor is POST /censor and CheckBatch is POST
+// /check/batch.
+type WordFilterClient interface {
+	// Check reports whether text contains bad words, with the most severe match
+	Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*CheckResponse, error)
+	// Censor reports every match and returns the text with them masked
+	Censor(ctx context.Context, in *CensorRequest, opts ...grpc.CallOption) (*CensorResponse, error)
+	// CheckBatch checks several keyed texts in one call
+	CheckBatch(ctx context.Context, in *CheckBatchRequest, opts ...grpc.CallOption) (*CheckBatchResponse, error)
+}
+
+type wordFilterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWordFilterClient(cc grpc.ClientConnInterface) WordFilterClient {
+	return &wordFilterClient{cc}
+}
+
+func (c *wordFilterClient) Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*CheckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckResponse)
+	err := c.cc.Invoke(ctx, WordFilter_Check_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wordFilterClient) Censor(ctx context.Context, in *CensorRequest, opts ...grpc.CallOption) (*CensorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CensorResponse)
+	err := c.cc.Invoke(ctx, WordFilter_Censor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wordFilterClient) CheckBatch(ctx context.Context, in *CheckBatchRequest, opts ...grpc.CallOption) (*CheckBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckBatchResponse)
+	err := c.cc.Invoke(ctx, WordFilter_CheckBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WordFilterServer is the server API for WordFilter service.
+// All implementations must embed UnimplementedWordFilterServer
+// for forward compatibility.
+//
+// WordFilter is the filter's contract for other services. It mirrors the HTTP
+// routes: Check is POST /check, Censor is POST /censor and CheckBatch is POST
+// /check/batch.
+type WordFilterServer interface {
+	// Check reports whether text contains bad words, with the most severe match
+	Check(context.Context, *CheckRequest) (*CheckResponse, error)
+	// Censor reports every match and returns the text with them masked
+	Censor(context.Context, *CensorRequest) (*CensorResponse, error)
+	// CheckBatch checks several keyed texts in one call
+	CheckBatch(context.Context, *CheckBatchRequest) (*CheckBatchResponse, error)
+	mustEmbedUnimplementedWordFilterServer()
+}
+
+// UnimplementedWordFilterServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWordFilterServer struct{}
+
+func (UnimplementedWordFilterServer) Check(context.Context, *CheckRequest) (*CheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Check not implemented")
+}
+func (UnimplementedWordFilterServer) Censor(context.Context, *CensorRequest) (*CensorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Censor not implemented")
+}
+func (UnimplementedWordFilterServer) CheckBatch(context.Context, *CheckBatchRequest) (*CheckBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckBatch not implemented")
+}
+func (UnimplementedWordFilterServer) mustEmbedUnimplementedWordFilterServer() {}
+func (UnimplementedWordFilterServer) testEmbeddedByValue()                    {}
+
+// UnsafeWordFilterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WordFilterServer will
+// result in compilation errors.
+type UnsafeWordFilterServer interface {
+	mustEmbedUnimplementedWordFilterServer()
+}
+
+func RegisterWordFilterServer(s grpc.ServiceRegistrar, srv WordFilterServer) {
+	// If the following call pancis, it indicates UnimplementedWordFilterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WordFilter_ServiceDesc, srv)
+}
+
+func _WordFilter_Check_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WordFilterServer).Check(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WordFilter_Check_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WordFilterServer).Check(ctx, req.(*CheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WordFilter_Censor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CensorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WordFilterServer).Censor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WordFilter_Censor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WordFilterServer).Censor(ctx, req.(*CensorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WordFilter_CheckBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WordFilterServer).CheckBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WordFilter_CheckBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WordFilterServer).CheckBatch(ctx, req.(*CheckBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WordFilter_ServiceDesc is the grpc.ServiceDesc for WordFilter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WordFilter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wordfilter.v1.WordFilter",
+	HandlerType: (*WordFilterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Check",
+			Handler:    _WordFilter_Check_Handler,
+		},
+		{
+			MethodName: "Censor",
+			Handler:    _WordFilter_Censor_Handler,
+		},
+		{
+			MethodName: "CheckBatch",
+			Handler:    _WordFilter_CheckBatch_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "wordfilter.proto",
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joy095/word-filter/config"
+)
+
+func init() {
+	config.LoadEnv()
+}
+
+// GetJWTSecret returns the secret shared with identity_service for signing
+// access tokens, or nil if JWT_SECRET is not set. There is no fallback: a
+// known secret would let anyone forge an admin's token.
+func GetJWTSecret() []byte {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil
+	}
+	return []byte(secret)
+}
+
+// IsAdmin reports whether a user may manage the word lists. Admins are listed
+// by user ID, comma separated, in ADMIN_USER_IDS.
+func IsAdmin(userID string) bool {
+	admins := strings.Split(os.Getenv("ADMIN_USER_IDS"), ",")
+	for i := range admins {
+		admins[i] = strings.TrimSpace(admins[i])
+	}
+	return userID != "" && slices.Contains(admins, userID)
+}
+
+// GetWatchInterval returns how often dictionary files are checked for changes;
+// zero turns watching off
+func GetWatchInterval() time.Duration {
+	value := os.Getenv("BADWORDS_WATCH_INTERVAL")
+	if value == "" {
+		return 10 * time.Second
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		fmt.Printf("WARNING: invalid BADWORDS_WATCH_INTERVAL %q, not watching dictionary files\n", value)
+		return 0
+	}
+	return interval
+}